@@ -0,0 +1,57 @@
+package main
+
+import "fmt"
+
+// Defaults for MatchConfig.AFKWarnAfter and AFKKickAfter (seconds)
+const (
+	DefaultAFKWarnAfter = 60.0
+	DefaultAFKKickAfter = 90.0
+	MaxAFKKickAfter     = 3600.0
+)
+
+// checkIdle advances every connected player's idle timer, warning and then
+// kicking those who stopped sending input. Dropped players are left to
+// the rejoin grace instead.
+func (g *Game) checkIdle(dt float64) {
+	warn, kick := g.config.AFKWarnAfter, g.config.AFKKickAfter
+	for _, p := range g.orderedPlayers() {
+		if p.Disconnected || p.IsBot {
+			continue
+		}
+		p.IdleT += dt
+		client := g.clients[p.ID]
+		switch {
+		case p.IdleT >= kick:
+			g.kick(p, "idle")
+		case p.IdleT >= warn && !p.idleWarned:
+			p.idleWarned = true
+			if client != nil {
+				client.SendJSON(Envelope{T: MsgError, Data: ErrorMsg{
+					Msg: fmt.Sprintf("no input: you will be removed in %.0fs", kick-p.IdleT),
+				}})
+			}
+		}
+	}
+}
+
+// kick removes a player from the game for reason, telling their client
+// and controller
+func (g *Game) kick(p *Player, reason string) {
+	msg := Envelope{T: MsgKicked, Data: KickedMsg{Reason: reason}}
+	if client, ok := g.clients[p.ID]; ok {
+		client.SendJSON(msg)
+	}
+	if ctrl, ok := g.controllers[p.ID]; ok {
+		ctrl.SendJSON(msg)
+	}
+	g.removePlayer(p.ID)
+	if g.onKick != nil {
+		g.onKick(p.ID)
+	}
+}
+
+// markActive resets a player's idle timer
+func (p *Player) markActive() {
+	p.IdleT = 0
+	p.idleWarned = false
+}
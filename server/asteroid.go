@@ -22,6 +22,11 @@ type Asteroid struct {
 
 // NewAsteroid spawns an asteroid at a random edge heading inward
 func NewAsteroid() *Asteroid {
+	return newAsteroidFrom(globalRand{})
+}
+
+// newAsteroidFrom is NewAsteroid drawing from r
+func newAsteroidFrom(r randSource) *Asteroid {
 	id := GenerateID(4)
 	a := &Asteroid{
 		ID:    id,
@@ -29,53 +34,53 @@ func NewAsteroid() *Asteroid {
 	}
 
 	// Random speed
-	speed := AsteroidMinSpeed + randFloat()*(AsteroidMaxSpeed-AsteroidMinSpeed)
+	speed := AsteroidMinSpeed + r.Float64()*(AsteroidMaxSpeed-AsteroidMinSpeed)
 
 	// Random spin
-	a.Spin = AsteroidSpinMin + randFloat()*(AsteroidSpinMax-AsteroidSpinMin)
-	if randFloat() < 0.5 {
+	a.Spin = AsteroidSpinMin + r.Float64()*(AsteroidSpinMax-AsteroidSpinMin)
+	if r.Float64() < 0.5 {
 		a.Spin = -a.Spin
 	}
 
 	// Pick random edge and aim inward
-	edge := int(randFloat() * 4)
+	edge := int(r.Float64() * 4)
 	switch edge {
 	case 0: // left
 		a.X = -AsteroidRadius
-		a.Y = randFloat() * WorldHeight
+		a.Y = r.Float64() * WorldHeight
 		// Aim toward right half
-		targetX := WorldWidth/2 + randFloat()*WorldWidth/2
-		targetY := randFloat() * WorldHeight
+		targetX := WorldWidth/2 + r.Float64()*WorldWidth/2
+		targetY := r.Float64() * WorldHeight
 		angle := math.Atan2(targetY-a.Y, targetX-a.X)
 		a.VX = math.Cos(angle) * speed
 		a.VY = math.Sin(angle) * speed
 	case 1: // right
 		a.X = WorldWidth + AsteroidRadius
-		a.Y = randFloat() * WorldHeight
-		targetX := randFloat() * WorldWidth / 2
-		targetY := randFloat() * WorldHeight
+		a.Y = r.Float64() * WorldHeight
+		targetX := r.Float64() * WorldWidth / 2
+		targetY := r.Float64() * WorldHeight
 		angle := math.Atan2(targetY-a.Y, targetX-a.X)
 		a.VX = math.Cos(angle) * speed
 		a.VY = math.Sin(angle) * speed
 	case 2: // top
-		a.X = randFloat() * WorldWidth
+		a.X = r.Float64() * WorldWidth
 		a.Y = -AsteroidRadius
-		targetX := randFloat() * WorldWidth
-		targetY := WorldHeight/2 + randFloat()*WorldHeight/2
+		targetX := r.Float64() * WorldWidth
+		targetY := WorldHeight/2 + r.Float64()*WorldHeight/2
 		angle := math.Atan2(targetY-a.Y, targetX-a.X)
 		a.VX = math.Cos(angle) * speed
 		a.VY = math.Sin(angle) * speed
 	default: // bottom
-		a.X = randFloat() * WorldWidth
+		a.X = r.Float64() * WorldWidth
 		a.Y = WorldHeight + AsteroidRadius
-		targetX := randFloat() * WorldWidth
-		targetY := randFloat() * WorldHeight / 2
+		targetX := r.Float64() * WorldWidth
+		targetY := r.Float64() * WorldHeight / 2
 		angle := math.Atan2(targetY-a.Y, targetX-a.X)
 		a.VX = math.Cos(angle) * speed
 		a.VY = math.Sin(angle) * speed
 	}
 
-	a.Rotation = randFloat() * math.Pi * 2
+	a.Rotation = r.Float64() * math.Pi * 2
 	return a
 }
 
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	BotDetectRange   = MobDetectRange * 1.5 // bots see a little farther than mobs
+	BotAimTolerance  = 0.15                 // radians off target a bot still fires at
+	BotWaypointReach = 150.0                // px from a wander waypoint before picking another
+)
+
+// botBrain is the steering state of an AI pilot
+type botBrain struct {
+	wx, wy float64 // wander waypoint while nothing is in range
+}
+
+// AddBot adds an AI pilot on behalf of the host. Bots are ordinary players
+// with no client: they score, die and respawn like anyone else.
+func (g *Game) AddBot(playerID string) (*Player, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if playerID == "" || g.host != playerID {
+		return nil, ErrNotHost
+	}
+	g.botSeq++
+	bot := g.addPlayer(fmt.Sprintf("Bot %d", g.botSeq), true)
+	if bot == nil {
+		return nil, ErrSessionFull
+	}
+	bot.brain = &botBrain{wx: bot.X, wy: bot.Y}
+	return bot, nil
+}
+
+// RemoveBot removes the most recently added bot on behalf of the host
+func (g *Game) RemoveBot(playerID string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if playerID == "" || g.host != playerID {
+		return ErrNotHost
+	}
+	var last *Player
+	for _, p := range g.players {
+		// Bot names count up, and so do their IDs in deterministic mode;
+		// compare names so the newest bot goes in either mode
+		if p.IsBot && (last == nil || botNewer(p, last)) {
+			last = p
+		}
+	}
+	if last == nil {
+		return ErrNoBots
+	}
+	g.removePlayer(last.ID)
+	return nil
+}
+
+func botNewer(a, b *Player) bool {
+	if len(a.Name) != len(b.Name) {
+		return len(a.Name) > len(b.Name)
+	}
+	return a.Name > b.Name
+}
+
+// HumanCount returns the number of players who aren't bots. Sessions with
+// only bots left are cleaned up like empty ones.
+func (g *Game) HumanCount() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	n := 0
+	for _, p := range g.players {
+		if !p.IsBot {
+			n++
+		}
+	}
+	return n
+}
+
+// connectedHumans is the number of human players with a live connection,
+// for callers holding the lock
+func (g *Game) connectedHumans() int {
+	n := 0
+	for _, p := range g.players {
+		if !p.IsBot && !p.Disconnected {
+			n++
+		}
+	}
+	return n
+}
+
+// thinkBots sets every living bot's input for this tick: hunt the nearest
+// ship or mob in range, leading the shot like a mob does, and wander
+// between waypoints otherwise
+func (g *Game) thinkBots() {
+	for _, bot := range g.orderedPlayers() {
+		if !bot.IsBot || !bot.Alive || bot.brain == nil {
+			continue
+		}
+		tx, ty, tvx, tvy, found := g.botTarget(bot)
+		if !found {
+			b := bot.brain
+			if DistanceSq(bot.X, bot.Y, b.wx, b.wy) < BotWaypointReach*BotWaypointReach {
+				r := bot.source()
+				b.wx = WorldWidth/4 + r.Float64()*WorldWidth/2
+				b.wy = WorldHeight/4 + r.Float64()*WorldHeight/2
+			}
+			bot.TargetX, bot.TargetY = b.wx, b.wy
+			bot.TargetR = math.Atan2(b.wy-bot.Y, b.wx-bot.X)
+			bot.SlowThresh = BotWaypointReach
+			bot.Firing = false
+			bot.Boosting = false
+			continue
+		}
+
+		// Lead the target, and fly at it: the pointer's distance slows the
+		// ship as it closes to MobOptimalRange
+		dist := Distance(bot.X, bot.Y, tx, ty)
+		t := dist / ProjectileSpeed
+		lx, ly := tx+tvx*t, ty+tvy*t
+		bot.TargetX, bot.TargetY = lx, ly
+		bot.TargetR = math.Atan2(ly-bot.Y, lx-bot.X)
+		bot.SlowThresh = MobOptimalRange
+		aimErr := math.Abs(NormalizeAngle(bot.TargetR - bot.Rotation))
+		bot.Firing = dist < MobShootRange && aimErr < BotAimTolerance
+		bot.Boosting = dist > 2*MobOptimalRange
+	}
+}
+
+// botTarget finds the nearest living ship or mob within BotDetectRange.
+// It walks g.players directly: thinkBots is already iterating the reused
+// orderedPlayers slice. Equal distances go to the lower ID so the choice
+// doesn't depend on map order.
+func (g *Game) botTarget(bot *Player) (x, y, vx, vy float64, found bool) {
+	best := BotDetectRange * BotDetectRange
+	bestID := ""
+	for _, p := range g.players {
+		if p == bot || !p.Alive {
+			continue
+		}
+		d2 := DistanceSq(bot.X, bot.Y, p.X, p.Y)
+		if d2 < best || (d2 == best && found && p.ID < bestID) {
+			best, bestID, x, y, vx, vy, found = d2, p.ID, p.X, p.Y, p.VX, p.VY, true
+		}
+	}
+	for _, m := range g.orderedMobs() {
+		if !m.Alive {
+			continue
+		}
+		if d2 := DistanceSq(bot.X, bot.Y, m.X, m.Y); d2 < best {
+			best, x, y, vx, vy, found = d2, m.X, m.Y, m.VX, m.VY, true
+		}
+	}
+	return x, y, vx, vy, found
+}
@@ -0,0 +1,49 @@
+package main
+
+import "sync/atomic"
+
+const maxGlobalEntities = 10000
+
+// EntityBudget tracks live entities across all sessions so spawning can be
+// throttled process-wide before the tick loops fall behind
+type EntityBudget struct {
+	limit int64
+	total atomic.Int64
+}
+
+// NewEntityBudget creates a budget allowing up to limit entities in total
+func NewEntityBudget(limit int) *EntityBudget {
+	return &EntityBudget{limit: int64(limit)}
+}
+
+// Add adjusts the live entity total by delta
+func (b *EntityBudget) Add(delta int) {
+	if b == nil || delta == 0 {
+		return
+	}
+	b.total.Add(int64(delta))
+}
+
+// Total returns the live entity count across sessions
+func (b *EntityBudget) Total() int {
+	if b == nil {
+		return 0
+	}
+	return int(b.total.Load())
+}
+
+// Limit returns the configured budget
+func (b *EntityBudget) Limit() int {
+	if b == nil {
+		return 0
+	}
+	return int(b.limit)
+}
+
+// Exceeded returns true when new spawns should be paused
+func (b *EntityBudget) Exceeded() bool {
+	if b == nil {
+		return false
+	}
+	return b.total.Load() >= b.limit
+}
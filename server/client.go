@@ -2,7 +2,9 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -16,6 +18,7 @@ const (
 	sendBufSize       = 256
 	maxMessagesPerSec = 50
 	maxNameLen        = 16
+	maxChatLen        = 200 // runes per chat line
 )
 
 // Client represents a WebSocket connection
@@ -27,6 +30,7 @@ type Client struct {
 	sessionID    string
 	remoteAddr   string
 	isController bool
+	isSpectator  bool // playerID holds the spectator ID
 	msgCount     int
 	msgResetAt   time.Time
 }
@@ -178,6 +182,30 @@ func (c *Client) handleMessage(raw []byte) {
 		c.handleCheck(env.D)
 	case MsgControl:
 		c.handleControl(env.D)
+	case MsgQuality:
+		c.handleQuality(env.D)
+	case MsgDebug:
+		c.handleDebug(env.D)
+	case MsgSeed:
+		c.handleSeed(env.D)
+	case MsgPause:
+		c.handlePause(true)
+	case MsgResume:
+		c.handlePause(false)
+	case MsgChat:
+		c.handleChat(env.D)
+	case MsgSpectate:
+		c.handleSpectate(env.D)
+	case MsgRejoin:
+		c.handleRejoin(env.D)
+	case MsgViewport:
+		c.handleViewport(env.D)
+	case MsgEnableDelta:
+		c.handleEnableDelta(env.D)
+	case MsgAddBot:
+		c.handleBot(true)
+	case MsgRemoveBot:
+		c.handleBot(false)
 	}
 }
 
@@ -206,8 +234,33 @@ func (c *Client) handleCreate(data json.RawMessage) {
 		sname = sname[:30]
 	}
 
-	sess := c.hub.sessions.CreateSession(sname)
+	if msg.Seed != 0 && !ValidSeed(msg.Seed) {
+		c.SendJSON(Envelope{T: MsgError, Data: ErrorMsg{Msg: ErrInvalidSeed.Error()}})
+		return
+	}
+	if !msg.MobWeights.Valid() {
+		c.SendJSON(Envelope{T: MsgError, Data: ErrorMsg{Msg: "invalid mob weights"}})
+		return
+	}
+	if _, err := ResolvePreset(msg.Preset, msg.Rules); err != nil {
+		c.SendJSON(Envelope{T: MsgError, Data: ErrorMsg{Msg: err.Error()}})
+		return
+	}
+	if ok, wait := c.hub.AllowCreate(c.remoteAddr); !ok {
+		secs := int(wait.Seconds()) + 1
+		c.SendJSON(Envelope{T: MsgError, Data: ErrorMsg{Msg: fmt.Sprintf("creating sessions too fast, try again in %ds", secs)}})
+		return
+	}
+
+	sess := c.hub.sessions.CreateSessionWith(sname, SessionOptions{
+		CloseWhenEmpty: msg.CloseWhenEmpty,
+		Seed:           msg.Seed,
+		MobWeights:     msg.MobWeights,
+		Preset:         msg.Preset,
+		Overrides:      msg.Rules,
+	})
 	if sess == nil {
+		c.hub.CancelCreate(c.remoteAddr)
 		c.SendJSON(Envelope{T: MsgError, Data: ErrorMsg{Msg: "too many active sessions"}})
 		return
 	}
@@ -235,19 +288,66 @@ func (c *Client) handleJoin(data json.RawMessage) {
 		return
 	}
 
+	// Already flying here: leaving first would drop the ship (and close a
+	// CloseWhenEmpty session) only to join again. A kicked client is still
+	// bound to the session but has no ship, so it joins normally.
+	if sess.ID == c.sessionID && !c.isController && !c.isSpectator && sess.Game.HasPlayer(c.playerID) {
+		return
+	}
+
 	player := sess.Game.AddPlayer(name)
 	if player == nil {
 		c.SendJSON(Envelope{T: MsgError, Data: ErrorMsg{Msg: "session full"}})
 		return
 	}
+	c.switchTo(sess, player)
+
+	sess.Game.SetClient(player.ID, c)
+	c.sendWelcome(sess, player)
+}
+
+// handleRejoin reattaches a reconnecting client to the ship it dropped
+func (c *Client) handleRejoin(data json.RawMessage) {
+	var msg RejoinMsg
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return
+	}
+	sess := c.hub.sessions.GetSession(msg.SID)
+	if sess == nil {
+		c.SendJSON(Envelope{T: MsgError, Data: ErrorMsg{Msg: "session not found"}})
+		return
+	}
+
+	player := sess.Game.Rejoin(msg.Token, c)
+	if player == nil {
+		c.SendJSON(Envelope{T: MsgError, Data: ErrorMsg{Msg: "rejoin expired"}})
+		return
+	}
+	c.switchTo(sess, player)
+	c.sendWelcome(sess, player)
+}
+
+// switchTo moves the connection onto a ship it has just been given. A
+// connection plays in one session at a time, so whatever it had before is
+// left, but only now: a failed join or rejoin keeps the old ship.
+func (c *Client) switchTo(sess *Session, player *Player) {
+	if c.sessionID != sess.ID || c.playerID != player.ID || c.isController || c.isSpectator {
+		c.handleLeave()
+	}
 	c.hub.sessions.MarkActive(sess.ID)
 	c.playerID = player.ID
 	c.sessionID = sess.ID
+}
 
-	sess.Game.SetClient(player.ID, c)
-
+// sendWelcome confirms a join or rejoin to the client
+func (c *Client) sendWelcome(sess *Session, player *Player) {
 	c.SendJSON(Envelope{T: MsgJoined, Data: map[string]string{"sid": sess.ID}})
-	c.SendJSON(Envelope{T: MsgWelcome, Data: WelcomeMsg{ID: player.ID, Ship: player.ShipType}})
+	c.SendJSON(Envelope{T: MsgWelcome, Data: WelcomeMsg{
+		ID:    player.ID,
+		Ship:  player.ShipType,
+		Host:  sess.Game.IsHost(player.ID),
+		Token: player.RejoinToken,
+	}})
 }
 
 // handleBinaryInput decodes a compact 8-byte binary input message
@@ -315,12 +415,18 @@ func (c *Client) handleLeave() {
 			if sess != nil {
 				sess.Game.RemoveController(c.playerID)
 			}
+		} else if c.isSpectator {
+			sess := c.hub.sessions.GetSession(c.sessionID)
+			if sess != nil {
+				sess.Game.RemoveSpectator(c.playerID)
+			}
 		} else {
 			c.hub.sessions.RemovePlayer(c.sessionID, c.playerID)
 		}
 		c.sessionID = ""
 		c.playerID = ""
 		c.isController = false
+		c.isSpectator = false
 	}
 }
 
@@ -339,6 +445,8 @@ func (c *Client) handleControl(data json.RawMessage) {
 		return
 	}
 
+	// Re-pointing a controller releases the ship it was driving before
+	c.handleLeave()
 	c.sessionID = msg.SID
 	c.playerID = msg.PlayerID
 	c.isController = true
@@ -346,3 +454,159 @@ func (c *Client) handleControl(data json.RawMessage) {
 	sess.Game.SetController(msg.PlayerID, c)
 	c.SendJSON(Envelope{T: MsgControlOK, Data: map[string]string{"pid": msg.PlayerID}})
 }
+
+// handleSpectate attaches the connection to a session as a watch-only spectator
+func (c *Client) handleSpectate(data json.RawMessage) {
+	var msg SpectateMsg
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return
+	}
+	sess := c.hub.sessions.GetSession(msg.SID)
+	if sess == nil {
+		c.SendJSON(Envelope{T: MsgError, Data: ErrorMsg{Msg: "session not found"}})
+		return
+	}
+
+	c.handleLeave()
+	c.sessionID = sess.ID
+	c.playerID = sess.Game.AddSpectator(c)
+	c.isSpectator = true
+	c.SendJSON(Envelope{T: MsgSpectating, Data: map[string]string{"sid": sess.ID}})
+}
+
+func (c *Client) handleQuality(data json.RawMessage) {
+	if c.sessionID == "" || c.playerID == "" || c.isController {
+		return
+	}
+	var msg QualityMsg
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return
+	}
+	sess := c.hub.sessions.GetSession(c.sessionID)
+	if sess == nil {
+		return
+	}
+	sess.Game.SetTieredUpdates(c.playerID, msg.Tiered)
+}
+
+func (c *Client) handleViewport(data json.RawMessage) {
+	if c.sessionID == "" || c.playerID == "" || c.isController {
+		return
+	}
+	var msg ViewportMsg
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return
+	}
+	sess := c.hub.sessions.GetSession(c.sessionID)
+	if sess == nil {
+		return
+	}
+	sess.Game.SetViewport(c.playerID, msg.W, msg.H)
+}
+
+func (c *Client) handleEnableDelta(data json.RawMessage) {
+	if c.sessionID == "" || c.playerID == "" || c.isController {
+		return
+	}
+	var msg EnableDeltaMsg
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return
+	}
+	sess := c.hub.sessions.GetSession(c.sessionID)
+	if sess == nil {
+		return
+	}
+	sess.Game.SetDelta(c.playerID, msg.On)
+}
+
+func (c *Client) handleDebug(data json.RawMessage) {
+	if c.sessionID == "" || c.playerID == "" || c.isController {
+		return
+	}
+	if !DebugFeedEnabled {
+		c.SendJSON(Envelope{T: MsgError, Data: ErrorMsg{Msg: "debug feed disabled"}})
+		return
+	}
+	var msg DebugMsg
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return
+	}
+	sess := c.hub.sessions.GetSession(c.sessionID)
+	if sess == nil {
+		return
+	}
+	sess.Game.SetDebugFeed(c.playerID, msg.On)
+}
+
+func (c *Client) handleSeed(data json.RawMessage) {
+	if c.sessionID == "" || c.playerID == "" || c.isController {
+		return
+	}
+	var msg SeedMsg
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return
+	}
+	sess := c.hub.sessions.GetSession(c.sessionID)
+	if sess == nil {
+		return
+	}
+	if err := sess.Game.SetSeed(c.playerID, msg.Seed); err != nil {
+		c.SendJSON(Envelope{T: MsgError, Data: ErrorMsg{Msg: err.Error()}})
+	}
+}
+
+func (c *Client) handleBot(add bool) {
+	if c.sessionID == "" || c.playerID == "" || c.isController {
+		return
+	}
+	sess := c.hub.sessions.GetSession(c.sessionID)
+	if sess == nil {
+		return
+	}
+	var err error
+	if add {
+		_, err = sess.Game.AddBot(c.playerID)
+	} else {
+		err = sess.Game.RemoveBot(c.playerID)
+	}
+	if err != nil {
+		c.SendJSON(Envelope{T: MsgError, Data: ErrorMsg{Msg: err.Error()}})
+	}
+}
+
+// handleChat relays a chat line. Chat counts toward the connection's
+// message rate limit like any other message.
+func (c *Client) handleChat(data json.RawMessage) {
+	if c.sessionID == "" || c.playerID == "" || c.isController {
+		return
+	}
+	var msg ChatSendMsg
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return
+	}
+	text := strings.TrimSpace(msg.Text)
+	if r := []rune(text); len(r) > maxChatLen {
+		text = strings.TrimSpace(string(r[:maxChatLen]))
+	}
+	if text == "" {
+		return
+	}
+	sess := c.hub.sessions.GetSession(c.sessionID)
+	if sess == nil {
+		return
+	}
+	sess.Game.Chat(c.playerID, text)
+}
+
+func (c *Client) handlePause(paused bool) {
+	if c.sessionID == "" || c.playerID == "" || c.isController {
+		return
+	}
+	sess := c.hub.sessions.GetSession(c.sessionID)
+	if sess == nil {
+		return
+	}
+	if !sess.Game.SetPaused(c.playerID, paused) {
+		c.SendJSON(Envelope{T: MsgError, Data: ErrorMsg{Msg: "only the host can pause"}})
+	}
+}
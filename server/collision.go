@@ -9,3 +9,15 @@ func CheckCollision(x1, y1, r1, x2, y2, r2 float64) bool {
 	return dist2 <= radSum*radSum
 }
 
+// SegmentCircleIntersect checks if the segment (x1,y1)-(x2,y2) passes within r of (cx,cy)
+func SegmentCircleIntersect(x1, y1, x2, y2, cx, cy, r float64) bool {
+	dx := x2 - x1
+	dy := y2 - y1
+	len2 := dx*dx + dy*dy
+	if len2 == 0 {
+		return DistanceSq(x1, y1, cx, cy) <= r*r
+	}
+	// Closest point on the segment to the circle center
+	t := Clamp(((cx-x1)*dx+(cy-y1)*dy)/len2, 0, 1)
+	return DistanceSq(x1+dx*t, y1+dy*t, cx, cy) <= r*r
+}
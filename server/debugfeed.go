@@ -0,0 +1,49 @@
+package main
+
+import "encoding/json"
+
+// DebugFeedEnabled allows clients to request MsgDebugState. It is set by the
+// -debug-feed flag and must stay off for public servers.
+var DebugFeedEnabled bool
+
+// SetDebugFeed toggles the debug feed for a player's client
+func (g *Game) SetDebugFeed(playerID string, enabled bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if p, ok := g.players[playerID]; ok {
+		p.DebugFeed = enabled
+	}
+}
+
+// countChecks records n broad-phase candidates examined by entity id
+func (g *Game) countChecks(id string, n int) {
+	if g.debugChecks != nil {
+		g.debugChecks[id] += n
+	}
+}
+
+// broadcastDebug sends a MsgDebugState to every client with the feed on and
+// resets the collision-check counters
+func (g *Game) broadcastDebug() {
+	msg := DebugStateMsg{
+		Tick:       g.tick,
+		CellSize:   g.grid.CellSize(),
+		Cols:       g.grid.Cols(),
+		Cells:      g.grid.Occupancy(nil),
+		Checks:     g.debugChecks,
+		MobTargets: make(map[string]string, len(g.mobs)),
+	}
+	for id, mob := range g.mobs {
+		msg.MobTargets[id] = mob.TargetID
+	}
+	data, err := json.Marshal(Envelope{T: MsgDebugState, Data: msg})
+	if err != nil {
+		return
+	}
+	for pid, client := range g.clients {
+		if p, ok := g.players[pid]; ok && p.DebugFeed {
+			client.SendRaw(data)
+		}
+	}
+	g.debugChecks = make(map[string]int, len(g.debugChecks))
+}
@@ -0,0 +1,123 @@
+package main
+
+import "math"
+
+const (
+	DeltaKeyframeEvery = 30  // delta frames between full keyframes (1s at BroadcastRate)
+	DeltaPosThreshold  = 1.0 // px an unchanged entity may drift before it is resent
+)
+
+// deltaView is one delta-mode client's record of what it was last sent,
+// so intermediate frames carry only entities that changed
+type deltaView struct {
+	frames uint64
+	sent   map[string]sentEntity
+	seen   map[string]struct{}
+}
+
+// sentEntity is an entity as last sent: its position, plus its other
+// state fields (position and velocity zeroed) for comparison
+type sentEntity struct {
+	x, y float64
+	key  any
+}
+
+func newDeltaView() *deltaView {
+	return &deltaView{
+		sent: make(map[string]sentEntity),
+		seen: make(map[string]struct{}),
+	}
+}
+
+// keep reports whether an entity must go in this frame, recording it as
+// sent if so. force marks a change the key doesn't capture (a velocity update).
+func (d *deltaView) keep(id string, x, y float64, key any, force bool) bool {
+	d.seen[id] = struct{}{}
+	if last, ok := d.sent[id]; ok && !force && last.key == key &&
+		math.Abs(x-last.x) <= DeltaPosThreshold && math.Abs(y-last.y) <= DeltaPosThreshold {
+		return false
+	}
+	d.sent[id] = sentEntity{x: x, y: y, key: key}
+	return true
+}
+
+// encode turns a culled frame into a keyframe or a delta frame in place.
+// Delta frames list only changed entities, plus the IDs of entities the
+// client was sent that have since died or left its view.
+func (d *deltaView) encode(st *GameState) {
+	if d.frames%DeltaKeyframeEvery == 0 {
+		clear(d.sent)
+		st.Key = true
+	}
+	d.frames++
+	clear(d.seen)
+
+	players := st.Players[:0]
+	for _, s := range st.Players {
+		k := s
+		k.X, k.Y, k.VX, k.VY = 0, 0, nil, nil
+		if d.keep(s.ID, s.X, s.Y, k, s.VX != nil) {
+			players = append(players, s)
+		}
+	}
+	st.Players = players
+	projs := st.Projectiles[:0]
+	for _, s := range st.Projectiles {
+		k := s
+		k.X, k.Y, k.VX, k.VY = 0, 0, nil, nil
+		if d.keep(s.ID, s.X, s.Y, k, s.VX != nil) {
+			projs = append(projs, s)
+		}
+	}
+	st.Projectiles = projs
+	mobs := st.Mobs[:0]
+	for _, s := range st.Mobs {
+		k := s
+		k.X, k.Y, k.VX, k.VY = 0, 0, nil, nil
+		if d.keep(s.ID, s.X, s.Y, k, s.VX != nil) {
+			mobs = append(mobs, s)
+		}
+	}
+	st.Mobs = mobs
+	asteroids := st.Asteroids[:0]
+	for _, s := range st.Asteroids {
+		k := s
+		k.X, k.Y = 0, 0
+		if d.keep(s.ID, s.X, s.Y, k, false) {
+			asteroids = append(asteroids, s)
+		}
+	}
+	st.Asteroids = asteroids
+	pickups := st.Pickups[:0]
+	for _, s := range st.Pickups {
+		k := s
+		k.X, k.Y = 0, 0
+		if d.keep(s.ID, s.X, s.Y, k, false) {
+			pickups = append(pickups, s)
+		}
+	}
+	st.Pickups = pickups
+
+	for id := range d.sent {
+		if _, ok := d.seen[id]; !ok {
+			st.Removed = append(st.Removed, id)
+			delete(d.sent, id)
+		}
+	}
+}
+
+// SetDelta switches a player's client between full frames and delta frames.
+// Enabling starts over with a keyframe.
+func (g *Game) SetDelta(playerID string, enabled bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	p, ok := g.players[playerID]
+	if !ok {
+		return
+	}
+	if enabled {
+		p.delta = newDeltaView()
+	} else {
+		p.delta = nil
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func lastState(t *testing.T, m *mockBroadcaster) GameState {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var gs GameState
+	if err := msgpack.Unmarshal(m.rawMsgs[len(m.rawMsgs)-1], &gs); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return gs
+}
+
+// allStates decodes every state frame m received, skipping JSON events
+func allStates(t *testing.T, m *mockBroadcaster) []GameState {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []GameState
+	for _, raw := range m.rawMsgs {
+		if len(raw) > 0 && raw[0] == '{' {
+			continue
+		}
+		var gs GameState
+		if err := msgpack.Unmarshal(raw, &gs); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		out = append(out, gs)
+	}
+	return out
+}
+
+func TestDeltaFramesSendOnlyChanges(t *testing.T) {
+	g := NewGame()
+	p := g.AddPlayer("Delta")
+	p.X, p.Y = 2000, 2000
+	other := g.AddPlayer("Still")
+	other.X, other.Y = 2200, 2000
+	pk := NewPickup()
+	pk.X, pk.Y = 1900, 2000
+	g.pickups[pk.ID] = pk
+	mock := &mockBroadcaster{}
+	g.SetClient(p.ID, mock)
+	g.SetDelta(p.ID, true)
+
+	g.broadcastState()
+	key := lastState(t, mock)
+	if !key.Key || len(key.Players) != 2 || len(key.Pickups) != 1 {
+		t.Fatalf("first delta frame should be a full keyframe, got key=%v players=%d pickups=%d",
+			key.Key, len(key.Players), len(key.Pickups))
+	}
+
+	g.broadcastState()
+	if st := lastState(t, mock); st.Key || len(st.Players) != 0 || len(st.Pickups) != 0 {
+		t.Errorf("nothing changed: expected an empty delta frame, got %d players, %d pickups",
+			len(st.Players), len(st.Pickups))
+	}
+
+	other.X += 50
+	other.HP -= 10
+	g.broadcastState()
+	st := lastState(t, mock)
+	if len(st.Players) != 1 || st.Players[0].ID != other.ID || st.Players[0].HP != other.HP {
+		t.Errorf("expected only the moved, damaged player in the delta, got %+v", st.Players)
+	}
+
+	delete(g.pickups, pk.ID)
+	g.broadcastState()
+	st = lastState(t, mock)
+	if len(st.Removed) != 1 || st.Removed[0] != pk.ID {
+		t.Errorf("collected pickup should be listed as removed, got %v", st.Removed)
+	}
+}
+
+func TestDeltaPeriodicKeyframe(t *testing.T) {
+	g := NewGame()
+	p := g.AddPlayer("Delta")
+	mock := &mockBroadcaster{}
+	g.SetClient(p.ID, mock)
+	g.SetDelta(p.ID, true)
+
+	keys := 0
+	for i := 0; i < 2*DeltaKeyframeEvery; i++ {
+		g.broadcastState()
+		if st := lastState(t, mock); st.Key {
+			if len(st.Players) != 1 {
+				t.Fatalf("keyframe should carry every entity in view, got %d players", len(st.Players))
+			}
+			keys++
+		}
+	}
+	if keys != 2 {
+		t.Errorf("expected a keyframe every %d frames, got %d in %d", DeltaKeyframeEvery, keys, 2*DeltaKeyframeEvery)
+	}
+
+	g.SetDelta(p.ID, false)
+	g.broadcastState()
+	if st := lastState(t, mock); st.Key || len(st.Players) != 1 {
+		t.Error("disabling delta should go back to plain full frames")
+	}
+}
+
+// byteCounter is a client that tallies state frame bytes, for benchmarks
+type byteCounter struct{ frames, bytes int }
+
+func (c *byteCounter) SendJSON(msg interface{}) {}
+func (c *byteCounter) SendRaw(data []byte)      {}
+func (c *byteCounter) SendBinary(data []byte)   { c.frames++; c.bytes += len(data) }
+
+// benchmarkStateBytes runs a 20-player session where half the pilots circle
+// and fire and half sit still, reporting the average state frame size
+func benchmarkStateBytes(b *testing.B, delta bool) {
+	g := NewGame()
+	var players []*Player
+	var counters []*byteCounter
+	for i := 0; i < 20; i++ {
+		p := g.AddPlayer("Bench")
+		p.HP, p.MaxHP = 1<<30, 1<<30
+		p.X, p.Y = 1600+float64(i%5)*200, 1600+float64(i/5)*200
+		c := &byteCounter{}
+		g.SetClient(p.ID, c)
+		if delta {
+			g.SetDelta(p.ID, true)
+		}
+		players = append(players, p)
+		counters = append(counters, c)
+	}
+	input := func(tick int) {
+		for i, p := range players {
+			if i%2 == 1 {
+				g.HandleInput(p.ID, ClientInput{MX: p.X, MY: p.Y})
+				continue
+			}
+			a := float64(tick+i*17) * 0.05
+			g.HandleInput(p.ID, ClientInput{
+				MX:     p.X + math.Cos(a)*300,
+				MY:     p.Y + math.Sin(a)*300,
+				Fire:   (tick/30+i)%3 != 0,
+				Thresh: 200,
+			})
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		input(i)
+		g.update()
+	}
+	b.StopTimer()
+	frames, bytes := 0, 0
+	for _, c := range counters {
+		frames += c.frames
+		bytes += c.bytes
+	}
+	if frames > 0 {
+		b.ReportMetric(float64(bytes)/float64(frames), "bytes/frame")
+	}
+}
+
+func BenchmarkStateBytesFull(b *testing.B)  { benchmarkStateBytes(b, false) }
+func BenchmarkStateBytesDelta(b *testing.B) { benchmarkStateBytes(b, true) }
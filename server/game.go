@@ -2,7 +2,12 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
 	"math"
+	"math/rand"
+	"sort"
 	"sync"
 	"time"
 
@@ -14,18 +19,70 @@ const (
 	BroadcastRate = 30               // state broadcasts per second
 	TickDuration  = time.Second / TickRate
 	BroadcastEvery = TickRate / BroadcastRate
+
+	// Adaptive broadcast: drop below BroadcastRate when nothing is happening
+	CalmBroadcastRate  = 20 // players alive, no shots and nothing moving near them
+	IdleBroadcastRate  = 10 // no living players and nothing in flight
+	CalmBroadcastEvery = TickRate / CalmBroadcastRate
+	IdleBroadcastEvery = TickRate / IdleBroadcastRate
+	activeSpeed        = 20.0 // px/s; slower ships and mobs count as still for the broadcast rate
+
+	// Velocity delta compression: a ship's velocity is only resent once it
+	// changes by this much on an axis (MatchConfig.PlayerVelDelta/MobVelDelta)
+	DefaultVelDelta = 5.0
+	MaxVelDelta     = 100.0
+
+	// Viewport culling: entities farther than the cull distance on either
+	// axis are left out of a client's frame
+	DefaultCullDist = 1200.0 // until the client reports its viewport
+	MinCullDist     = 400.0
+	MaxCullDist     = 2000.0
+	CullMargin      = 100.0 // beyond half the viewport diagonal
+
+	// Distance-tiered updates (opt-in per client via MsgQuality)
+	NearTierDist = 600.0
+	MidTierDist  = 900.0
+	MidTierEvery = 2 // broadcasts between mid-tier updates
+	FarTierEvery = 4 // broadcasts between far-tier updates
+
+	// DefaultCatchUpSteps bounds the fixed sub-steps one wakeup may run when
+	// the loop falls behind, so a stall can't snowball into ever longer
+	// wakeups (MatchConfig.CatchUpSteps overrides it)
+	DefaultCatchUpSteps = 5
+	MaxCatchUpSteps     = 30
+)
+
+// Distance tier bits, reported in GameState.Tiers for tiered clients
+const (
+	TierNear uint8 = 1 << iota
+	TierMid
+	TierFar
 )
 
 const (
 	maxProjectilesPerSession = 500
 	maxPlayersPerSession     = 20
 	maxMobsPerSession        = 8
-	maxAsteroidsPerSession   = 5
-	maxPickupsPerSession     = 4
+
+	// Live caps scale with player count between these bounds (see recomputeCaps)
+	minProjectilesPerSession = 100
+	projectilesPerPlayer     = 20 // ~13 live shots at full fire rate, plus headroom
+	minMobsPerSession        = 4
+	playersPerExtraMob       = 3 // default for MatchConfig.PlayersPerExtraMob
+	maxAsteroidsPerSession   = 5 // default for MatchConfig.MaxAsteroids
+	maxPickupsPerSession     = 4 // default for MatchConfig.MaxPickups
 	MobSpawnInterval         = 7.0
 	AsteroidSpawnInterval    = 10.0
 	PickupSpawnInterval      = 20.0
 	DeathScorePenalty        = 10
+	simSeedSalt              = 0x51d0c0de // derives simRng's seed from the session seed
+)
+
+// Hard limits on the per-session caps a MatchConfig may ask for
+const (
+	mobCapLimit      = 24
+	asteroidCapLimit = 20
+	pickupCapLimit   = 12
 )
 
 // Broadcaster interface for sending messages to clients
@@ -37,36 +94,81 @@ type Broadcaster interface {
 
 // Game holds the state for one game session
 type Game struct {
-	mu          sync.RWMutex
-	players     map[string]*Player
-	projectiles map[string]*Projectile
-	mobs        map[string]*Mob
-	asteroids   map[string]*Asteroid
-	pickups     map[string]*Pickup
-	clients     map[string]Broadcaster // playerID -> client
-	controllers map[string]Broadcaster // playerID -> phone controller
-	tick        uint64
-	running     bool
-	stop        chan struct{}
-	nextShip    int
+	mu             sync.RWMutex
+	players        map[string]*Player
+	projectiles    []*Projectile // dense, compacted each tick (no ID lookups needed); see update
+	mobs           map[string]*Mob
+	asteroids      map[string]*Asteroid
+	pickups        map[string]*Pickup
+	clients        map[string]Broadcaster // playerID -> client
+	controllers    map[string]Broadcaster // playerID -> phone controller
+	spectators     map[string]Broadcaster // spectator ID -> watch-only client
+	tick           uint64
+	lastBcast      uint64  // tick of the last state broadcast
+	lastScoreboard uint64  // tick of the last MsgScoreboard
+	disconnectSeq  uint64  // numbers player drops, see DisconnectPlayer
+	respawnWaveT   float64 // seconds until the next respawn wave
+	bcastSeq       uint64  // number of state broadcasts so far (drives distance tiers)
+	running        bool
+	accum          time.Duration // real time not yet simulated (game loop only)
+	lastDropLog    time.Time     // last "dropped simulation time" warning
+	catchingUp     bool          // running a non-final catch-up sub-step
+	paused         bool          // gameplay frozen; state is still broadcast
+	host           string        // player allowed to pause/resume
+	hostLeft       bool          // a host left the game (see SessionManager.afterRemove)
+	botSeq         int           // bots added so far, for naming
+	stop           chan struct{}
+	nextShip       int
+	config         MatchConfig
+	seed           int64
+	rng            *rand.Rand // seeded source for PvE spawns
+	simRng         *rand.Rand // respawn points and AI jitter; apart from rng so play can't shift spawn patterns
+	seedLocked     bool       // set on the first tick with two humans connected (see SetSeed)
+	obstacles      []Obstacle // static asteroids, placed from the seed
+	projCap        int        // live projectile cap for the current player count
+	mobCap         int        // live mob cap for the current player count
+	idSeq          uint64     // entity ID counter in deterministic mode
+
+	// Process-wide entity budget (shared across sessions, may be nil)
+	budget      *EntityBudget
+	budgetCount int // entities last reported to budget
 
 	mobSpawnCD      float64
 	asteroidSpawnCD float64
 	pickupSpawnCD   float64
+	mobSayCD        float64 // session-wide cooldown between mob phrases
 
 	// Spatial hash grid for broad-phase collision detection
 	grid SpatialGrid
 
+	// Reusable per-tick iteration orders (sorted by ID in deterministic mode)
+	orderPlayers []*Player
+	orderMobs    []*Mob
+
 	// Flat entity lists for spatial grid indexing (rebuilt each tick)
 	flatPlayers   []*Player
-	flatProjs     []*Projectile
 	flatMobs      []*Mob
 	flatAsteroids []*Asteroid
 	flatPickups   []*Pickup
 
+	// update() timings for slow-tick logging and /api/debug
+	tickTimes tickTimer
+	tag       string                // identifies the session in logs
+	onKick    func(playerID string) // called under mu after a player is kicked
+
+	// Freelist of despawned projectiles, reused to avoid per-shot allocation
+	projPool []*Projectile
+
 	// Reusable query buffer for spatial grid lookups
 	queryBuf []EntityRef
 
+	// Rammers to kick once the collision pass is done (see noteRam)
+	ramKicks []*Player
+
+	// Developer debug feed: on while any client has it enabled
+	debugActive bool
+	debugChecks map[string]int // broad-phase candidates per entity since the last debug frame
+
 	// Delta compression: last-sent velocity per entity
 	lastVX map[string]float64
 	lastVY map[string]float64
@@ -86,33 +188,141 @@ type Game struct {
 	filtPickups   []PickupState
 }
 
-// NewGame creates a new Game
+// NewGame creates a new Game with the default match config
 func NewGame() *Game {
-	return &Game{
-		players:         make(map[string]*Player),
-		projectiles:     make(map[string]*Projectile),
-		mobs:            make(map[string]*Mob),
-		asteroids:       make(map[string]*Asteroid),
-		pickups:         make(map[string]*Pickup),
-		clients:         make(map[string]Broadcaster),
-		controllers:     make(map[string]Broadcaster),
-		stop:            make(chan struct{}),
-		mobSpawnCD:      MobSpawnInterval,
-		asteroidSpawnCD: AsteroidSpawnInterval,
-		pickupSpawnCD:   PickupSpawnInterval,
-		lastVX:          make(map[string]float64, maxPlayersPerSession+maxMobsPerSession),
-		lastVY:          make(map[string]float64, maxPlayersPerSession+maxMobsPerSession),
-		bcastPlayers:    make([]playerWithPos, 0, maxPlayersPerSession),
-		bcastMobs:       make([]mobWithPos, 0, maxMobsPerSession),
-		bcastAsteroids:  make([]asteroidWithPos, 0, maxAsteroidsPerSession),
-		bcastPickups:    make([]pickupWithPos, 0, maxPickupsPerSession),
-		bcastProjs:      make([]projWithPos, 0, 64),
-		filtPlayers:     make([]PlayerState, 0, maxPlayersPerSession),
-		filtProjs:       make([]ProjectileState, 0, 64),
-		filtMobs:        make([]MobState, 0, maxMobsPerSession),
-		filtAsteroids:   make([]AsteroidState, 0, maxAsteroidsPerSession),
-		filtPickups:     make([]PickupState, 0, maxPickupsPerSession),
+	return NewGameWithConfig(DefaultMatchConfig())
+}
+
+// NewGameWithConfig creates a new Game using the given match config
+func NewGameWithConfig(cfg MatchConfig) *Game {
+	g := &Game{
+		config:         cfg.Sanitize(),
+		tag:            "game",
+		players:        make(map[string]*Player),
+		projectiles:    make([]*Projectile, 0, 64),
+		mobs:           make(map[string]*Mob),
+		asteroids:      make(map[string]*Asteroid),
+		pickups:        make(map[string]*Pickup),
+		clients:        make(map[string]Broadcaster),
+		controllers:    make(map[string]Broadcaster),
+		spectators:     make(map[string]Broadcaster),
+		stop:           make(chan struct{}),
+		lastVX:         make(map[string]float64, maxPlayersPerSession+maxMobsPerSession),
+		lastVY:         make(map[string]float64, maxPlayersPerSession+maxMobsPerSession),
+		bcastPlayers:   make([]playerWithPos, 0, maxPlayersPerSession),
+		bcastMobs:      make([]mobWithPos, 0, maxMobsPerSession),
+		bcastAsteroids: make([]asteroidWithPos, 0, maxAsteroidsPerSession),
+		bcastPickups:   make([]pickupWithPos, 0, maxPickupsPerSession),
+		bcastProjs:     make([]projWithPos, 0, 64),
+		filtPlayers:    make([]PlayerState, 0, maxPlayersPerSession),
+		filtProjs:      make([]ProjectileState, 0, 64),
+		filtMobs:       make([]MobState, 0, maxMobsPerSession),
+		filtAsteroids:  make([]AsteroidState, 0, maxAsteroidsPerSession),
+		filtPickups:    make([]PickupState, 0, maxPickupsPerSession),
+	}
+	g.mobSpawnCD = g.config.MobSpawnInterval
+	g.asteroidSpawnCD = g.config.AsteroidSpawnInterval
+	g.pickupSpawnCD = g.config.PickupSpawnInterval
+	g.grid.init(g.config.SpatialCellSize)
+	g.recomputeCaps()
+	seed := g.config.Seed
+	if seed == 0 {
+		seed = rand.Int63n(MaxSeed) + 1
+	}
+	g.reseed(seed)
+	return g
+}
+
+// reseed restarts every seeded source from seed. simRng is reseeded in
+// place because players and mobs hold on to it.
+func (g *Game) reseed(seed int64) {
+	g.seed = seed
+	g.rng = rand.New(rand.NewSource(seed))
+	if g.simRng == nil {
+		g.simRng = rand.New(rand.NewSource(seed ^ simSeedSalt))
+	} else {
+		g.simRng.Seed(seed ^ simSeedSalt)
+	}
+	g.obstacles = PlaceStaticAsteroids(seed, g.config.StaticAsteroids)
+}
+
+// obstaclesMsg returns the static asteroid field as a message
+func (g *Game) obstaclesMsg() Envelope {
+	list := make([]ObstacleState, len(g.obstacles))
+	for i := range g.obstacles {
+		list[i] = g.obstacles[i].ToState()
+	}
+	return Envelope{T: MsgObstacles, Data: ObstaclesMsg{List: list}}
+}
+
+// Seed returns the session's PvE spawn seed
+func (g *Game) Seed() int64 {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.seed
+}
+
+var (
+	ErrNotHost       = errors.New("only the host can do that")
+	ErrSeedLocked    = errors.New("seed is locked once the match has started")
+	ErrInvalidSeed   = errors.New("seed must be between 1 and 2^53-1")
+	ErrSessionFull   = errors.New("session full")
+	ErrNoBots        = errors.New("no bots to remove")
+	ErrUnknownPreset = errors.New("unknown match preset")
+)
+
+// SetSeed lets the host change the spawn seed, so a scrim can replay
+// another session's patterns. The seed stays open while the host waits
+// alone and locks on the first tick with a second human connected. Any
+// PvE entities spawned before then are cleared and the spawn timers
+// restart, so the match plays out from the new seed.
+func (g *Game) SetSeed(playerID string, seed int64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if playerID == "" || g.host != playerID {
+		return ErrNotHost
+	}
+	if !ValidSeed(seed) {
+		return ErrInvalidSeed
 	}
+	if g.seedLocked {
+		return ErrSeedLocked
+	}
+	g.reseed(seed)
+	clear(g.mobs)
+	clear(g.asteroids)
+	clear(g.pickups)
+	g.mobSpawnCD = g.config.MobSpawnInterval
+	g.asteroidSpawnCD = g.config.AsteroidSpawnInterval
+	g.pickupSpawnCD = g.config.PickupSpawnInterval
+	g.broadcastMsg(Envelope{T: MsgSeedSet, Data: SeedMsg{Seed: seed}})
+	if g.config.StaticAsteroids > 0 {
+		g.broadcastMsg(g.obstaclesMsg())
+	}
+	return nil
+}
+
+// recomputeCaps scales the projectile and mob caps with the player count,
+// so a full lobby gets more headroom and a 1v1 keeps less in memory. The mob
+// base and per-player growth are scaled by MaxMobs relative to the default
+// cap, so a raised MaxMobs is actually reachable with a full lobby.
+func (g *Game) recomputeCaps() {
+	n := len(g.players)
+	g.projCap = min(minProjectilesPerSession+n*projectilesPerPlayer, maxProjectilesPerSession)
+	g.mobCap = 0
+	if n >= g.config.MinPlayersForMobs {
+		mobs := g.config.MaxMobs
+		base := max(1, minMobsPerSession*mobs/maxMobsPerSession)
+		extra := n * mobs / (g.config.PlayersPerExtraMob * maxMobsPerSession)
+		g.mobCap = min(base+extra, mobs)
+	}
+}
+
+// Caps returns the current live projectile and mob caps
+func (g *Game) Caps() (projectiles, mobs int) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.projCap, g.mobCap
 }
 
 // Run starts the game loop
@@ -124,16 +334,61 @@ func (g *Game) Run() {
 	ticker := time.NewTicker(TickDuration)
 	defer ticker.Stop()
 
+	last := time.Now()
 	for {
 		select {
-		case <-ticker.C:
-			g.update()
+		case now := <-ticker.C:
+			g.advance(now.Sub(last))
+			last = now
 		case <-g.stop:
 			return
 		}
 	}
 }
 
+// advance simulates elapsed real time in fixed TickDuration sub-steps,
+// carrying the remainder to the next call. If the loop fell behind, up to
+// config.CatchUpSteps ticks run back to back; only the last one may
+// broadcast. Time beyond the cap is dropped with a warning.
+// Returns the number of ticks run.
+func (g *Game) advance(elapsed time.Duration) int {
+	g.accum += elapsed
+	steps := int(g.accum / TickDuration)
+	if limit := g.config.CatchUpSteps; steps > limit {
+		dropped := g.accum - time.Duration(limit)*TickDuration
+		steps = limit
+		g.accum = time.Duration(steps) * TickDuration
+		if now := time.Now(); now.Sub(g.lastDropLog) >= slowTickLogEvery {
+			log.Printf("%s fell behind: dropped %v of simulation time (catch-up cap %d ticks)", g.tag, dropped, limit)
+			g.lastDropLog = now
+		}
+	}
+	for i := 0; i < steps; i++ {
+		g.catchingUp = i < steps-1
+		g.timedUpdate()
+	}
+	g.catchingUp = false
+	g.accum -= time.Duration(steps) * TickDuration
+	return steps
+}
+
+// timedUpdate runs one tick and records how long it took
+func (g *Game) timedUpdate() {
+	g.timeTick(g.update)
+}
+
+// timeTick runs tick and records its duration in the tick stats
+func (g *Game) timeTick(tick func()) {
+	start := time.Now()
+	tick()
+	g.tickTimes.record(g.tag, time.Since(start))
+}
+
+// TickStats returns update() timings for this session
+func (g *Game) TickStats() TickStats {
+	return g.tickTimes.snapshot()
+}
+
 // Stop terminates the game loop
 func (g *Game) Stop() {
 	g.mu.Lock()
@@ -142,22 +397,39 @@ func (g *Game) Stop() {
 		g.running = false
 		close(g.stop)
 	}
+	g.budget.Add(-g.budgetCount)
+	g.budgetCount = 0
 }
 
 // AddPlayer adds a new player to the game
 func (g *Game) AddPlayer(name string) *Player {
 	g.mu.Lock()
 	defer g.mu.Unlock()
+	return g.addPlayer(name, false)
+}
 
+func (g *Game) addPlayer(name string, bot bool) *Player {
 	if len(g.players) >= maxPlayersPerSession {
 		return nil
 	}
 
-	id := GenerateID(4)
+	id := g.entityID(4)
 	ship := g.nextShip % 3
 	g.nextShip++
 	player := NewPlayer(id, name, ship)
+	player.rng = g.simRng
+	player.X, player.Y = spawnPoint(g.simRng)
+	g.placeClear(&player.X, &player.Y, PlayerRadius, g.simRng, spawnPoint)
+	player.RejoinToken = GenerateID(16)
+	player.Boost = g.config.Boost
+	player.TurnAssist = g.config.TurnAssist
+	player.Stamina = g.config.Boost.Stamina
+	player.IsBot = bot
 	g.players[id] = player
+	g.recomputeCaps()
+	if g.host == "" && !bot {
+		g.host = id
+	}
 	return player
 }
 
@@ -165,9 +437,120 @@ func (g *Game) AddPlayer(name string) *Player {
 func (g *Game) RemovePlayer(id string) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
+	g.removePlayer(id)
+}
+
+func (g *Game) removePlayer(id string) {
 	delete(g.players, id)
 	delete(g.clients, id)
 	delete(g.controllers, id)
+	g.recomputeCaps()
+	if g.host == id {
+		g.hostLeft = true
+		g.host = g.nextHost()
+		if g.host == "" {
+			g.paused = false
+		}
+	}
+}
+
+// nextHost picks the remaining human player with the lowest ID, or "" if
+// there is none
+func (g *Game) nextHost() string {
+	next := ""
+	for pid, p := range g.players {
+		if p.IsBot {
+			continue
+		}
+		if next == "" || pid < next {
+			next = pid
+		}
+	}
+	return next
+}
+
+// IsHost reports whether playerID may pause and resume the session
+func (g *Game) IsHost(playerID string) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return playerID != "" && g.host == playerID
+}
+
+// HostLeft returns true once a host has left the game
+func (g *Game) HostLeft() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.hostLeft
+}
+
+// End tells everyone still in the game why it is closing, then stops it
+func (g *Game) End(reason string) {
+	g.mu.Lock()
+	g.broadcastMsg(Envelope{T: MsgKicked, Data: KickedMsg{Reason: reason}})
+	g.mu.Unlock()
+	g.Stop()
+}
+
+// SetPaused freezes or resumes gameplay on behalf of playerID. Only the host
+// may do so; returns false if the request was rejected.
+func (g *Game) SetPaused(playerID string, paused bool) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if playerID == "" || g.host != playerID {
+		return false
+	}
+	if g.paused == paused {
+		return true
+	}
+	g.paused = paused
+	g.broadcastMsg(Envelope{T: MsgPaused, Data: PausedMsg{Paused: paused, By: g.playerName(playerID)}})
+	return true
+}
+
+// Paused reports whether gameplay is frozen
+func (g *Game) Paused() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.paused
+}
+
+// Chat relays a player's chat line to the session; false if they aren't in it
+func (g *Game) Chat(playerID, text string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	p, ok := g.players[playerID]
+	if !ok {
+		return false
+	}
+	g.broadcastMsg(Envelope{T: MsgChatMsg, Data: ChatBroadcastMsg{PlayerID: p.ID, Name: p.Name, Text: text}})
+	return true
+}
+
+// AddSpectator attaches a watch-only client and returns its spectator ID.
+// Spectators get the full world state but are not players.
+func (g *Game) AddSpectator(client Broadcaster) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	id := GenerateID(4) // not an entity, so deterministic IDs are unaffected
+	g.spectators[id] = client
+	if len(g.obstacles) > 0 {
+		client.SendJSON(g.obstaclesMsg())
+	}
+	return id
+}
+
+// RemoveSpectator detaches a spectator
+func (g *Game) RemoveSpectator(id string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.spectators, id)
+}
+
+// SpectatorCount returns the number of spectators watching
+func (g *Game) SpectatorCount() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return len(g.spectators)
 }
 
 // SetController associates a phone controller with a player
@@ -175,6 +558,10 @@ func (g *Game) SetController(playerID string, client Broadcaster) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 	g.controllers[playerID] = client
+	if p, ok := g.players[playerID]; ok {
+		p.markActive()
+		p.TurnAssist = true // thumb aiming is coarse
+	}
 	// Notify desktop client that a controller is now active
 	if main, ok := g.clients[playerID]; ok {
 		main.SendJSON(Envelope{T: MsgCtrlOn})
@@ -186,6 +573,9 @@ func (g *Game) RemoveController(playerID string) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 	delete(g.controllers, playerID)
+	if p, ok := g.players[playerID]; ok {
+		p.TurnAssist = g.config.TurnAssist
+	}
 	// Notify desktop client that the controller disconnected
 	if main, ok := g.clients[playerID]; ok {
 		main.SendJSON(Envelope{T: MsgCtrlOff})
@@ -205,6 +595,12 @@ func (g *Game) SetClient(playerID string, client Broadcaster) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 	g.clients[playerID] = client
+	if p, ok := g.players[playerID]; ok {
+		p.sent.reset()
+	}
+	if len(g.obstacles) > 0 {
+		client.SendJSON(g.obstaclesMsg())
+	}
 }
 
 // HandleInput processes input from a player
@@ -223,6 +619,7 @@ func (g *Game) HandleInput(playerID string, input ClientInput) {
 	if dx*dx+dy*dy > 25 { // > 5px distance
 		p.TargetR = math.Atan2(dy, dx)
 	}
+	p.markActive()
 	p.Firing = input.Fire
 	p.Boosting = input.Boost
 	p.TargetX = input.MX
@@ -230,6 +627,33 @@ func (g *Game) HandleInput(playerID string, input ClientInput) {
 	p.SlowThresh = Clamp(input.Thresh, 50, 400)
 }
 
+// SetTieredUpdates toggles distance-tiered state updates for a player's client
+func (g *Game) SetTieredUpdates(playerID string, enabled bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if p, ok := g.players[playerID]; ok {
+		p.TieredUpdates = enabled
+	}
+}
+
+// SetViewport sizes a player's culling radius from their screen size
+func (g *Game) SetViewport(playerID string, w, h float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if p, ok := g.players[playerID]; ok {
+		p.CullDist = CullDistFor(w, h)
+	}
+}
+
+// CullDistFor returns the cull distance for a w x h viewport: half its
+// diagonal plus CullMargin, clamped so a client can't ask for the whole map
+func CullDistFor(w, h float64) float64 {
+	if w <= 0 || h <= 0 {
+		return DefaultCullDist
+	}
+	return Clamp(math.Hypot(w, h)/2+CullMargin, MinCullDist, MaxCullDist)
+}
+
 // PlayerCount returns the number of players
 func (g *Game) PlayerCount() int {
 	g.mu.RLock()
@@ -244,49 +668,104 @@ func (g *Game) update() {
 
 	dt := 1.0 / float64(TickRate)
 	g.tick++
+	if g.paused {
+		// Keep clients in sync with the frozen scene; no timers advance
+		g.maybeBroadcast()
+		return
+	}
+	if g.mobSayCD > 0 {
+		g.mobSayCD -= dt
+	}
+	if !g.seedLocked && g.connectedHumans() >= 2 {
+		g.seedLocked = true // the match is on: see SetSeed
+	}
+
+	// Warn, then kick, players who stopped sending input
+	g.checkIdle(dt)
+	g.thinkBots()
 
 	// Update players
-	for _, p := range g.players {
-		p.Update(dt)
+	waves := g.config.RespawnWaveInterval > 0
+	if waves {
+		g.respawnWave(dt)
+	}
+	debugActive := false
+	for _, p := range g.orderedPlayers() {
+		if waves && !p.Alive {
+			p.RespawnT -= dt // held for the next wave, see respawnWave
+		} else {
+			wasDead := !p.Alive
+			p.Update(dt)
+			if wasDead && p.Alive {
+				g.placeClear(&p.X, &p.Y, PlayerRadius, p.source(), spawnPoint)
+				g.broadcastTeleport(p)
+			}
+		}
+		debugActive = debugActive || p.DebugFeed
 
 		// Handle firing
-		if p.CanFire() && len(g.projectiles) < maxProjectilesPerSession {
-			proj := NewProjectile(p)
-			g.projectiles[proj.ID] = proj
+		if p.CanFire() && len(g.projectiles) < g.projCap {
+			proj := g.acquireProjectile()
+			proj.resetFromPlayer(p)
+			g.assignProjectileID(proj)
+			g.projectiles = append(g.projectiles, proj)
 			p.FireCD = FireCooldown
+			p.ShotsFired++
+			p.camFired = true
+		}
+		if p.Alive && g.tick%KillCamSampleEvery == 0 {
+			p.RecordCamFrame()
 		}
 	}
 
-	// Update projectiles
-	for id, proj := range g.projectiles {
+	// Update projectiles, compacting dead ones out in place. Entries only
+	// move here and in buildSpatialGrid's sort, before the grid is filled;
+	// the collision pass just clears Alive and new shots are appended. The
+	// grid's slice indices stay valid for the tick that built them, so
+	// they don't need generation IDs.
+	alive := g.projectiles[:0]
+	for _, proj := range g.projectiles {
 		proj.Update(dt)
-		if !proj.Alive {
-			delete(g.projectiles, id)
+		if proj.Alive {
+			alive = append(alive, proj)
+		} else {
+			g.releaseProjectile(proj)
 		}
 	}
+	for i := len(alive); i < len(g.projectiles); i++ {
+		g.projectiles[i] = nil
+	}
+	g.projectiles = alive
 
 	// Update mobs
-	for id, mob := range g.mobs {
+	for _, mob := range g.orderedMobs() {
 		wantFire := mob.Update(dt, g.players, g.projectiles)
 		if !mob.Alive {
-			delete(g.mobs, id)
+			delete(g.mobs, mob.ID)
 			continue
 		}
 		// Broadcast mob phrase if any
 		if mob.PendingPhrase != "" {
-			g.broadcastMsg(Envelope{T: MsgMobSay, Data: MobSayMsg{
-				MobID: mob.ID, Text: mob.PendingPhrase,
-			}})
+			g.mobSay(mob.ID, mob.PendingPhrase)
 			mob.PendingPhrase = ""
 		}
-		if wantFire && len(g.projectiles) < maxProjectilesPerSession {
-			proj := NewMobProjectile(mob)
-			g.projectiles[proj.ID] = proj
+		if wantFire && len(g.projectiles) < g.projCap {
+			proj := g.acquireProjectile()
+			proj.resetFromMob(mob)
+			g.assignProjectileID(proj)
+			g.projectiles = append(g.projectiles, proj)
 		}
 	}
 
+	g.debugActive = debugActive
+	if !debugActive {
+		g.debugChecks = nil
+	} else if g.debugChecks == nil {
+		g.debugChecks = make(map[string]int)
+	}
+
 	// Mob-mob collisions (soft repulsion, explode if fast)
-	g.checkMobMobCollisions()
+	g.checkMobMobCollisions(dt)
 
 	// Update asteroids
 	for id, ast := range g.asteroids {
@@ -307,22 +786,68 @@ func (g *Game) update() {
 	// Build spatial grid for broad-phase collision
 	g.buildSpatialGrid()
 
-	// Check collisions
+	// Check collisions (static cover first, so it shields what's behind it)
+	g.checkObstacleCollisions()
+	if g.config.ProjectileCancel {
+		g.checkProjectileProjectileCollisions()
+	}
 	g.checkCollisions()
 	g.checkPlayerCollisions()
 	g.checkProjectileMobCollisions()
 	g.checkAsteroidPlayerCollisions()
 	g.checkAsteroidMobCollisions()
 	g.checkProjectileAsteroidCollisions()
+	g.pullPickups(dt)
 	g.checkPlayerPickupCollisions()
 	g.checkPlayerMobCollisions()
 
 	// Spawn entities
 	g.spawnEntities(dt)
+	g.reportBudget()
 
-	// Broadcast state
-	if g.tick%BroadcastEvery == 0 {
-		g.broadcastState()
+	g.maybeBroadcast()
+	g.maybeBroadcastScoreboard()
+}
+
+// respawnWave advances the wave timer and, at each wave, respawns every
+// dead player whose respawn delay has elapsed
+func (g *Game) respawnWave(dt float64) {
+	g.respawnWaveT -= dt
+	if g.respawnWaveT > 0 {
+		return
+	}
+	g.respawnWaveT += g.config.RespawnWaveInterval
+	for _, p := range g.orderedPlayers() {
+		if !p.Alive && p.RespawnT <= 0 {
+			p.Respawn()
+			g.placeClear(&p.X, &p.Y, PlayerRadius, p.source(), spawnPoint)
+			g.broadcastTeleport(p)
+		}
+	}
+}
+
+// broadcastTeleport tells clients a ship jumped (e.g. respawned elsewhere)
+// so they snap to it instead of interpolating across the map
+func (g *Game) broadcastTeleport(p *Player) {
+	g.broadcastMsg(Envelope{T: MsgTeleport, Data: TeleportMsg{ID: p.ID, X: round1(p.X), Y: round1(p.Y)}})
+}
+
+// maybeBroadcast sends state if a broadcast is due at the current activity rate
+func (g *Game) maybeBroadcast() {
+	if g.catchingUp || g.tick-g.lastBcast < g.broadcastEvery() {
+		return
+	}
+	if len(g.clients) == 0 && len(g.controllers) == 0 && len(g.spectators) == 0 {
+		// Nobody connected: skip marshaling entirely. Velocity deltas are
+		// reset so the first frame after a client connects is complete.
+		clear(g.lastVX)
+		clear(g.lastVY)
+		return
+	}
+	g.lastBcast = g.tick
+	g.broadcastState()
+	if g.debugActive {
+		g.broadcastDebug()
 	}
 }
 
@@ -332,7 +857,7 @@ func (g *Game) buildSpatialGrid() {
 
 	// Build flat lists for indexed lookup
 	g.flatPlayers = g.flatPlayers[:0]
-	for _, p := range g.players {
+	for _, p := range g.orderedPlayers() {
 		if p.Alive {
 			idx := len(g.flatPlayers)
 			g.flatPlayers = append(g.flatPlayers, p)
@@ -340,17 +865,16 @@ func (g *Game) buildSpatialGrid() {
 		}
 	}
 
-	g.flatProjs = g.flatProjs[:0]
-	for _, proj := range g.projectiles {
-		if proj.Alive {
-			idx := len(g.flatProjs)
-			g.flatProjs = append(g.flatProjs, proj)
-			g.grid.Insert(proj.X, proj.Y, EntityRef{Kind: 'r', Idx: idx})
-		}
+	// Projectiles were compacted this tick, so the slice is already all-alive
+	// and can be indexed directly. Resolve hits in a stable order so
+	// same-tick trades don't depend on spawn order.
+	sort.Slice(g.projectiles, func(i, j int) bool { return g.projectiles[i].ID < g.projectiles[j].ID })
+	for idx, proj := range g.projectiles {
+		g.grid.Insert(proj.X, proj.Y, EntityRef{Kind: 'r', Idx: idx})
 	}
 
 	g.flatMobs = g.flatMobs[:0]
-	for _, mob := range g.mobs {
+	for _, mob := range g.orderedMobs() {
 		if mob.Alive {
 			idx := len(g.flatMobs)
 			g.flatMobs = append(g.flatMobs, mob)
@@ -361,30 +885,86 @@ func (g *Game) buildSpatialGrid() {
 	g.flatAsteroids = g.flatAsteroids[:0]
 	for _, ast := range g.asteroids {
 		if ast.Alive {
-			idx := len(g.flatAsteroids)
 			g.flatAsteroids = append(g.flatAsteroids, ast)
-			g.grid.InsertCircle(ast.X, ast.Y, AsteroidRadius, EntityRef{Kind: 'a', Idx: idx})
 		}
 	}
+	if g.config.Deterministic {
+		sort.Slice(g.flatAsteroids, func(i, j int) bool { return g.flatAsteroids[i].ID < g.flatAsteroids[j].ID })
+	}
+	for idx, ast := range g.flatAsteroids {
+		g.grid.InsertCircle(ast.X, ast.Y, AsteroidRadius, EntityRef{Kind: 'a', Idx: idx})
+	}
+
+	for idx := range g.obstacles {
+		o := &g.obstacles[idx]
+		g.grid.InsertCircle(o.X, o.Y, o.R, EntityRef{Kind: 'o', Idx: idx})
+	}
 
 	g.flatPickups = g.flatPickups[:0]
 	for _, pk := range g.pickups {
 		if pk.Alive {
-			idx := len(g.flatPickups)
 			g.flatPickups = append(g.flatPickups, pk)
-			g.grid.InsertCircle(pk.X, pk.Y, PickupRadius, EntityRef{Kind: 'k', Idx: idx})
 		}
 	}
+	if g.config.Deterministic {
+		sort.Slice(g.flatPickups, func(i, j int) bool { return g.flatPickups[i].ID < g.flatPickups[j].ID })
+	}
+	for idx, pk := range g.flatPickups {
+		g.grid.InsertCircle(pk.X, pk.Y, PickupRadius, EntityRef{Kind: 'k', Idx: idx})
+	}
+}
+
+// orderedPlayers returns the players in tick order: map order normally,
+// by ID in deterministic mode. The slice is reused between calls.
+func (g *Game) orderedPlayers() []*Player {
+	g.orderPlayers = g.orderPlayers[:0]
+	for _, p := range g.players {
+		g.orderPlayers = append(g.orderPlayers, p)
+	}
+	if g.config.Deterministic {
+		sort.Slice(g.orderPlayers, func(i, j int) bool { return g.orderPlayers[i].ID < g.orderPlayers[j].ID })
+	}
+	return g.orderPlayers
+}
+
+// orderedMobs is orderedPlayers for mobs
+func (g *Game) orderedMobs() []*Mob {
+	g.orderMobs = g.orderMobs[:0]
+	for _, m := range g.mobs {
+		g.orderMobs = append(g.orderMobs, m)
+	}
+	if g.config.Deterministic {
+		sort.Slice(g.orderMobs, func(i, j int) bool { return g.orderMobs[i].ID < g.orderMobs[j].ID })
+	}
+	return g.orderMobs
+}
+
+// entityID returns a new entity ID: random normally, a counter in
+// deterministic mode so repeated runs produce the same IDs (and ID order)
+func (g *Game) entityID(byteLen int) string {
+	if !g.config.Deterministic {
+		return GenerateID(byteLen)
+	}
+	g.idSeq++
+	return fmt.Sprintf("%0*x", byteLen*2, g.idSeq)
+}
+
+// assignProjectileID replaces a fresh projectile's random ID in deterministic mode
+func (g *Game) assignProjectileID(proj *Projectile) {
+	if g.config.Deterministic {
+		proj.ID = g.entityID(3)
+	}
 }
 
 // checkCollisions checks projectile-player collisions using spatial grid
 func (g *Game) checkCollisions() {
-	const queryR = ProjectileRadius + PlayerRadius
-	for _, proj := range g.flatProjs {
+	for _, proj := range g.projectiles {
 		if !proj.Alive {
 			continue
 		}
-		g.queryBuf = g.grid.QueryBuf(proj.X, proj.Y, queryR, g.queryBuf[:0])
+		qx, qy, queryR := proj.QueryArea(PlayerRadius)
+		g.queryBuf = g.grid.QueryBuf(qx, qy, queryR, g.queryBuf[:0])
+		g.countChecks(proj.ID, len(g.queryBuf))
 		nearby := g.queryBuf
 		for _, ref := range nearby {
 			if ref.Kind != 'p' {
@@ -394,9 +974,13 @@ func (g *Game) checkCollisions() {
 			if !p.Alive || p.ID == proj.OwnerID {
 				continue
 			}
-			if CheckCollision(proj.X, proj.Y, ProjectileRadius, p.X, p.Y, PlayerRadius) {
+			if proj.HitsCircle(p.X, p.Y, PlayerRadius) {
 				died := p.TakeDamage(proj.Damage)
 				proj.Alive = false
+				if shooter, ok := g.players[proj.OwnerID]; ok {
+					shooter.RecordHit(proj.Damage)
+					p.LastHitBy = shooter.ID
+				}
 
 				// Broadcast hit event
 				g.broadcastMsg(Envelope{T: MsgHit, Data: HitMsg{
@@ -406,30 +990,14 @@ func (g *Game) checkCollisions() {
 
 				if died {
 					p.Score -= DeathScorePenalty
-					// Award kill to shooter
+					// Award kill to shooter (even if the shooter died earlier this
+					// tick — a trade credits both sides once)
 					if killer, ok := g.players[proj.OwnerID]; ok {
-						killer.Score++
-						killMsg := Envelope{T: MsgKill, Data: KillMsg{
-							KillerID:   killer.ID,
-							KillerName: killer.Name,
-							VictimID:   p.ID,
-							VictimName: p.Name,
-						}}
-						g.broadcastMsg(killMsg)
-
-						if client, ok := g.clients[p.ID]; ok {
-							client.SendJSON(Envelope{T: MsgDeath, Data: DeathMsg{
-								KillerID:   killer.ID,
-								KillerName: killer.Name,
-							}})
-						}
+						g.creditPlayerKill(killer, p)
 					} else {
 						// Killed by mob — mob celebrates
 						if killerMob, ok := g.mobs[proj.OwnerID]; ok && killerMob.Alive {
-							phrase := pickPhraseAlways("kill_player")
-							g.broadcastMsg(Envelope{T: MsgMobSay, Data: MobSayMsg{
-								MobID: killerMob.ID, Text: phrase,
-							}})
+							g.mobSay(killerMob.ID, pickPhraseAlways("kill_player"))
 						}
 						g.broadcastMsg(Envelope{T: MsgKill, Data: KillMsg{
 							KillerID: proj.OwnerID, KillerName: "Mob",
@@ -442,6 +1010,7 @@ func (g *Game) checkCollisions() {
 							}})
 						}
 					}
+					g.deathBlast(p)
 				}
 				break
 			}
@@ -449,6 +1018,141 @@ func (g *Game) checkCollisions() {
 	}
 }
 
+// checkProjectileProjectileCollisions destroys pairs of shots from different
+// owners whose paths met this tick
+func (g *Game) checkProjectileProjectileCollisions() {
+	for i, a := range g.projectiles {
+		if !a.Alive {
+			continue
+		}
+		// The other shot is indexed at its end position, which can be up to
+		// its own step (about the same as ours) past the meeting point
+		step := 0.0
+		if a.HasPrev {
+			step = Distance(a.PrevX, a.PrevY, a.X, a.Y)
+		}
+		qx, qy, queryR := a.QueryArea(ProjectileRadius + step)
+		g.queryBuf = g.grid.QueryBuf(qx, qy, queryR, g.queryBuf[:0])
+		for _, ref := range g.queryBuf {
+			// Each pair once, from its lower index
+			if ref.Kind != 'r' || ref.Idx <= i {
+				continue
+			}
+			b := g.projectiles[ref.Idx]
+			if !b.Alive || b.OwnerID == a.OwnerID || !projectilesMeet(a, b) {
+				continue
+			}
+			a.Alive = false
+			b.Alive = false
+			g.broadcastMsg(Envelope{T: MsgSpark, Data: SparkMsg{
+				X: round1((a.X + b.X) / 2), Y: round1((a.Y + b.Y) / 2),
+			}})
+			break
+		}
+	}
+}
+
+// projectilesMeet reports whether two shots came within contact range at
+// any point during this tick, by sweeping b's path relative to a
+func projectilesMeet(a, b *Projectile) bool {
+	if !a.HasPrev || !b.HasPrev {
+		return CheckCollision(a.X, a.Y, ProjectileRadius, b.X, b.Y, ProjectileRadius)
+	}
+	return SegmentCircleIntersect(b.PrevX-a.PrevX, b.PrevY-a.PrevY, b.X-a.X, b.Y-a.Y, 0, 0, 2*ProjectileRadius)
+}
+
+// creditPlayerKill awards killer for victim's death and notifies the session
+func (g *Game) creditPlayerKill(killer, victim *Player) {
+	killer.Score++
+	dist := Distance(killer.X, killer.Y, victim.X, victim.Y)
+	killer.RecordKill(dist, g.time())
+	g.streakReward(killer)
+	g.broadcastMsg(Envelope{T: MsgKill, Data: KillMsg{
+		KillerID:   killer.ID,
+		KillerName: killer.Name,
+		VictimID:   victim.ID,
+		VictimName: victim.Name,
+		Dist:       int(dist),
+	}})
+	victim.KilledBy = killer.ID
+
+	if client, ok := g.clients[victim.ID]; ok {
+		client.SendJSON(Envelope{T: MsgDeath, Data: DeathMsg{
+			KillerID:   killer.ID,
+			KillerName: killer.Name,
+			Cam:        killer.KillCam(),
+		}})
+	}
+}
+
+// streakReward scores the streak bonus for killer's latest kill and
+// announces streak milestones
+func (g *Game) streakReward(killer *Player) {
+	if killer.Streak >= StreakBonusFrom {
+		killer.Score += StreakBonusScore
+	}
+	if tier := streakTier(killer.Streak); tier != "" {
+		g.broadcastMsg(Envelope{T: MsgStreak, Data: StreakMsg{
+			PlayerID: killer.ID, Name: killer.Name, Streak: killer.Streak, Tier: tier,
+		}})
+	}
+}
+
+// deathBlast damages other ships near a destroyed one when death explosions
+// are enabled. Damage and kills are credited to whoever last hit the ship
+// (the ship itself if nobody did), so blasts can chain. The killer isn't
+// spared at point-blank range, but dying in their own victim's blast
+// counts as a death with no kill credit.
+func (g *Game) deathBlast(ship *Player) {
+	radius, dmg := g.config.DeathBlastRadius, g.config.DeathBlastDamage
+	if radius <= 0 || dmg <= 0 {
+		return
+	}
+	credit, ok := g.players[ship.LastHitBy]
+	if !ok {
+		credit = ship
+	}
+	// flatPlayers isn't rebuilt mid-tick, so it's safe to walk while chaining
+	for _, p := range g.flatPlayers {
+		if !p.Alive || p == ship {
+			continue
+		}
+		if !CheckCollision(ship.X, ship.Y, radius, p.X, p.Y, PlayerRadius) {
+			continue
+		}
+		died := p.TakeDamage(dmg)
+		attacker := credit.ID
+		if p == credit {
+			attacker = "blast" // caught in their own victim's explosion
+		} else if credit != ship {
+			credit.RecordHit(dmg)
+			p.LastHitBy = credit.ID
+		}
+		g.broadcastMsg(Envelope{T: MsgHit, Data: HitMsg{
+			X: p.X, Y: p.Y, Dmg: dmg,
+			VictimID: p.ID, AttackerID: attacker,
+		}})
+		if !died {
+			continue
+		}
+		p.Score -= DeathScorePenalty
+		if p == credit {
+			g.broadcastMsg(Envelope{T: MsgKill, Data: KillMsg{
+				KillerID: "blast", KillerName: "Explosion",
+				VictimID: p.ID, VictimName: p.Name,
+			}})
+			if client, ok := g.clients[p.ID]; ok {
+				client.SendJSON(Envelope{T: MsgDeath, Data: DeathMsg{
+					KillerID: "blast", KillerName: "Explosion",
+				}})
+			}
+		} else {
+			g.creditPlayerKill(credit, p)
+		}
+		g.deathBlast(p)
+	}
+}
+
 // checkPlayerCollisions checks ship-to-ship collisions (both die)
 func (g *Game) checkPlayerCollisions() {
 	players := g.flatPlayers // reuse pre-built alive-player list
@@ -459,10 +1163,12 @@ func (g *Game) checkPlayerCollisions() {
 				continue
 			}
 			if CheckCollision(a.X, a.Y, PlayerRadius, b.X, b.Y, PlayerRadius) {
-				a.TakeDamage(a.HP)
-				b.TakeDamage(b.HP)
+				a.Wreck()
+				b.Wreck()
 				a.Score -= DeathScorePenalty
 				b.Score -= DeathScorePenalty
+				a.RecordRam()
+				b.RecordRam()
 
 				// Notify kills (mutual)
 				killMsg1 := Envelope{T: MsgKill, Data: KillMsg{
@@ -478,33 +1184,110 @@ func (g *Game) checkPlayerCollisions() {
 
 				if client, ok := g.clients[a.ID]; ok {
 					client.SendJSON(Envelope{T: MsgDeath, Data: DeathMsg{
-						KillerID: b.ID, KillerName: b.Name,
+						KillerID: b.ID, KillerName: b.Name, Cam: b.KillCam(),
 					}})
 				}
 				if client, ok := g.clients[b.ID]; ok {
 					client.SendJSON(Envelope{T: MsgDeath, Data: DeathMsg{
-						KillerID: a.ID, KillerName: a.Name,
+						KillerID: a.ID, KillerName: a.Name, Cam: a.KillCam(),
 					}})
 				}
+				g.deathBlast(a)
+				g.deathBlast(b)
+				if p := rammer(a, b); g.noteRam(p) {
+					g.ramKicks = append(g.ramKicks, p)
+				}
 			}
 		}
 	}
+	// Kick after the pass so no later pair touches a removed player
+	for i, p := range g.ramKicks {
+		g.kick(p, "ramming")
+		g.ramKicks[i] = nil
+	}
+	g.ramKicks = g.ramKicks[:0]
+}
+
+// broadcastEvery returns how many ticks to wait between state broadcasts:
+// full rate while shots are in flight or a living player has moving ships or
+// mobs nearby, the calm rate for players alone in quiet space, and the idle
+// rate when nobody is alive
+func (g *Game) broadcastEvery() uint64 {
+	if g.paused {
+		return IdleBroadcastEvery
+	}
+	if len(g.projectiles) > 0 {
+		return BroadcastEvery
+	}
+	alive := false
+	for _, p := range g.players {
+		if !p.Alive {
+			continue
+		}
+		alive = true
+		if g.activeNear(p) {
+			return BroadcastEvery
+		}
+	}
+	if alive {
+		return CalmBroadcastEvery
+	}
+	return IdleBroadcastEvery
+}
+
+// activeNear returns true if another ship or a mob is moving within
+// NearTierDist of p, where interpolating at a lower rate would show
+func (g *Game) activeNear(p *Player) bool {
+	near := func(x, y, vx, vy float64) bool {
+		return math.Abs(x-p.X) <= NearTierDist && math.Abs(y-p.Y) <= NearTierDist &&
+			vx*vx+vy*vy > activeSpeed*activeSpeed
+	}
+	for _, o := range g.players {
+		if o != p && o.Alive && near(o.X, o.Y, o.VX, o.VY) {
+			return true
+		}
+	}
+	for _, m := range g.mobs {
+		if near(m.X, m.Y, m.VX, m.VY) {
+			return true
+		}
+	}
+	return false
+}
+
+// distanceTier classifies an entity by its per-axis distance from the viewer
+func distanceTier(dx, dy float64) uint8 {
+	d := dx
+	if dy > d {
+		d = dy
+	}
+	switch {
+	case d <= NearTierDist:
+		return TierNear
+	case d <= MidTierDist:
+		return TierMid
+	default:
+		return TierFar
+	}
 }
 
 // entityWithPos holds a converted entity state with raw position for viewport culling
 type projWithPos struct {
-	state ProjectileState
-	x, y  float64
+	state  ProjectileState
+	x, y   float64
+	vx, vy float64
 }
 
 type playerWithPos struct {
-	state PlayerState
-	x, y  float64
+	state  PlayerState
+	x, y   float64
+	vx, vy float64 // full velocity, for clients the shared delta would leave stale
 }
 
 type mobWithPos struct {
-	state MobState
-	x, y  float64
+	state  MobState
+	x, y   float64
+	vx, vy float64
 }
 
 type asteroidWithPos struct {
@@ -517,47 +1300,80 @@ type pickupWithPos struct {
 	x, y  float64
 }
 
+// sentView records which entities a client got in its previous frame.
+// Velocity is omitted against what the last broadcast carried, which a
+// client only has if the entity was in that frame too; one that comes back
+// after being culled or tier-skipped must carry its velocity again.
+type sentView struct {
+	prev, cur map[string]struct{}
+}
+
+// next starts a new frame
+func (v *sentView) next() {
+	if v.cur == nil {
+		v.prev, v.cur = make(map[string]struct{}), make(map[string]struct{})
+	}
+	v.prev, v.cur = v.cur, v.prev
+	clear(v.cur)
+}
+
+// resend records id as sent this frame and reports whether the client
+// missed it last frame
+func (v *sentView) resend(id string) bool {
+	v.cur[id] = struct{}{}
+	_, had := v.prev[id]
+	return !had
+}
+
+// reset forgets everything, for a new connection
+func (v *sentView) reset() {
+	v.prev, v.cur = nil, nil
+}
+
 // broadcastState sends the current game state to all clients with per-client viewport culling
 func (g *Game) broadcastState() {
-	// Delta compression threshold — skip velocity when change is tiny
-	const velDelta = 5.0
+	// Delta compression thresholds — skip velocity when change is tiny
+	compress := !g.config.DisableVelocityDelta
+	playerVelDelta, mobVelDelta := g.config.PlayerVelDelta, g.config.MobVelDelta
 
 	// Pre-convert all entities to state once, keeping raw positions for culling
 	g.bcastPlayers = g.bcastPlayers[:0]
 	for _, p := range g.players {
 		ps := p.ToState()
+		p.Wrapped = false
 		// Omit velocity if unchanged since last broadcast
 		vx := *ps.VX
 		vy := *ps.VY
 		prevVX, prevVY := g.lastVX[p.ID], g.lastVY[p.ID]
 		dx := vx - prevVX; if dx < 0 { dx = -dx }
 		dy := vy - prevVY; if dy < 0 { dy = -dy }
-		if dx < velDelta && dy < velDelta {
+		if compress && dx < playerVelDelta && dy < playerVelDelta {
 			ps.VX = nil
 			ps.VY = nil
 		} else {
 			g.lastVX[p.ID] = vx
 			g.lastVY[p.ID] = vy
 		}
-		g.bcastPlayers = append(g.bcastPlayers, playerWithPos{state: ps, x: p.X, y: p.Y})
+		g.bcastPlayers = append(g.bcastPlayers, playerWithPos{state: ps, x: p.X, y: p.Y, vx: vx, vy: vy})
 	}
 	g.bcastMobs = g.bcastMobs[:0]
 	for _, mob := range g.mobs {
 		if mob.Alive {
 			ms := mob.ToState()
+			mob.Wrapped = false
 			vx := *ms.VX
 			vy := *ms.VY
 			prevVX, prevVY := g.lastVX[mob.ID], g.lastVY[mob.ID]
 			dx := vx - prevVX; if dx < 0 { dx = -dx }
 			dy := vy - prevVY; if dy < 0 { dy = -dy }
-			if dx < velDelta && dy < velDelta {
+			if compress && dx < mobVelDelta && dy < mobVelDelta {
 				ms.VX = nil
 				ms.VY = nil
 			} else {
 				g.lastVX[mob.ID] = vx
 				g.lastVY[mob.ID] = vy
 			}
-			g.bcastMobs = append(g.bcastMobs, mobWithPos{state: ms, x: mob.X, y: mob.Y})
+			g.bcastMobs = append(g.bcastMobs, mobWithPos{state: ms, x: mob.X, y: mob.Y, vx: vx, vy: vy})
 		}
 	}
 	g.bcastAsteroids = g.bcastAsteroids[:0]
@@ -574,11 +1390,33 @@ func (g *Game) broadcastState() {
 	}
 	g.bcastProjs = g.bcastProjs[:0]
 	for _, proj := range g.projectiles {
-		g.bcastProjs = append(g.bcastProjs, projWithPos{state: proj.ToState(), x: proj.X, y: proj.Y})
+		// Shots that hit something this tick are only compacted out next tick
+		if !proj.Alive {
+			continue
+		}
+		ps := proj.ToState()
+		proj.Wrapped = false
+		// Shots fly straight, so velocity only goes out when fired or
+		// reflected, and to clients seeing the shot for the first time
+		vx, vy := *ps.VX, *ps.VY
+		if compress && proj.velSent {
+			ps.VX = nil
+			ps.VY = nil
+		}
+		proj.velSent = true
+		g.bcastProjs = append(g.bcastProjs, projWithPos{state: ps, x: proj.X, y: proj.Y, vx: vx, vy: vy})
 	}
 
-	// Viewport culling radius (half-viewport + margin)
-	const cullDist = 1200.0
+	// Distance tiers for clients that opted into tiered updates: near
+	// entities go out every broadcast, mid/far ones every Nth
+	g.bcastSeq++
+	tiers := TierNear
+	if g.bcastSeq%MidTierEvery == 0 {
+		tiers |= TierMid
+	}
+	if g.bcastSeq%FarTierEvery == 0 {
+		tiers |= TierFar
+	}
 
 	// Cache marshaled data per player to reuse for controllers
 	playerData := make(map[string][]byte, len(g.clients))
@@ -588,38 +1426,78 @@ func (g *Game) broadcastState() {
 		if !ok {
 			continue
 		}
-		px, py := player.X, player.Y
+		view := g.viewOf(player)
+		px, py := view.X, view.Y
+		cullDist := player.CullDist
+		if cullDist == 0 {
+			cullDist = DefaultCullDist
+		}
+		// Delta frames would report tier-skipped entities as removed, so
+		// delta mode takes precedence over tiers
+		tiered := player.TieredUpdates && player.delta == nil
+		clientTiers := TierNear | TierMid | TierFar
+		if tiered {
+			clientTiers = tiers
+		}
+		// A ship or shot that wrapped an edge goes out whatever its tier:
+		// the flag is only set for this frame
+		inView := func(dx, dy float64, wrapped bool) bool {
+			if dx > cullDist || dy > cullDist {
+				return false
+			}
+			return wrapped || clientTiers&distanceTier(dx, dy) != 0
+		}
+
+		sent := &player.sent
+		if compress {
+			sent.next()
+		}
 
 		// Filter all entity types by viewport distance
 		g.filtPlayers = g.filtPlayers[:0]
-		for _, p := range g.bcastPlayers {
+		for i := range g.bcastPlayers {
+			p := &g.bcastPlayers[i]
 			dx := p.x - px; if dx < 0 { dx = -dx }
 			dy := p.y - py; if dy < 0 { dy = -dy }
-			if dx <= cullDist && dy <= cullDist {
-				g.filtPlayers = append(g.filtPlayers, p.state)
+			if inView(dx, dy, p.state.Wrap) {
+				st := p.state
+				if compress && sent.resend(st.ID) && st.VX == nil {
+					st.VX, st.VY = &p.vx, &p.vy
+				}
+				g.filtPlayers = append(g.filtPlayers, st)
 			}
 		}
 		g.filtProjs = g.filtProjs[:0]
-		for _, p := range g.bcastProjs {
+		for i := range g.bcastProjs {
+			p := &g.bcastProjs[i]
 			dx := p.x - px; if dx < 0 { dx = -dx }
 			dy := p.y - py; if dy < 0 { dy = -dy }
-			if dx <= cullDist && dy <= cullDist {
-				g.filtProjs = append(g.filtProjs, p.state)
+			if inView(dx, dy, p.state.Wrap) {
+				st := p.state
+				if compress && sent.resend(st.ID) && st.VX == nil {
+					st.VX, st.VY = &p.vx, &p.vy
+				}
+				g.filtProjs = append(g.filtProjs, st)
 			}
 		}
 		g.filtMobs = g.filtMobs[:0]
-		for _, m := range g.bcastMobs {
+		for i := range g.bcastMobs {
+			m := &g.bcastMobs[i]
 			dx := m.x - px; if dx < 0 { dx = -dx }
 			dy := m.y - py; if dy < 0 { dy = -dy }
-			if dx <= cullDist && dy <= cullDist {
-				g.filtMobs = append(g.filtMobs, m.state)
+			if inView(dx, dy, m.state.Wrap) {
+				st := m.state
+				if compress && sent.resend(st.ID) && st.VX == nil {
+					st.VX, st.VY = &m.vx, &m.vy
+				}
+				g.filtMobs = append(g.filtMobs, st)
 			}
 		}
 		g.filtAsteroids = g.filtAsteroids[:0]
 		for _, a := range g.bcastAsteroids {
 			dx := a.x - px; if dx < 0 { dx = -dx }
 			dy := a.y - py; if dy < 0 { dy = -dy }
-			if dx <= cullDist && dy <= cullDist {
+			if inView(dx, dy, false) {
 				g.filtAsteroids = append(g.filtAsteroids, a.state)
 			}
 		}
@@ -627,7 +1505,7 @@ func (g *Game) broadcastState() {
 		for _, pk := range g.bcastPickups {
 			dx := pk.x - px; if dx < 0 { dx = -dx }
 			dy := pk.y - py; if dy < 0 { dy = -dy }
-			if dx <= cullDist && dy <= cullDist {
+			if inView(dx, dy, false) {
 				g.filtPickups = append(g.filtPickups, pk.state)
 			}
 		}
@@ -639,62 +1517,121 @@ func (g *Game) broadcastState() {
 			Asteroids:   g.filtAsteroids,
 			Pickups:     g.filtPickups,
 			Tick:        g.tick,
+			Paused:      g.paused,
+			Following:   player.Following,
+		}
+		if tiered {
+			state.Tiers = clientTiers
+		}
+		if player.delta != nil {
+			player.delta.encode(&state)
 		}
 
 		data, err := msgpack.Marshal(&state)
 		if err != nil {
 			continue
 		}
-		playerData[playerID] = data
+		if player.delta == nil {
+			// Delta frames only make sense to their own client
+			playerData[playerID] = data
+		}
 		client.SendBinary(data)
 	}
 
-	// Send to controllers using same data as their linked player
-	var fallbackData []byte
+	// Send to controllers using same data as their linked player.
+	// Spectators, and controllers without a frame, get the full unculled
+	// state (marshaled once).
+	var fullData []byte
+	full := func() []byte {
+		if fullData == nil {
+			fullData = g.marshalFullState()
+		}
+		return fullData
+	}
 	for playerID, client := range g.controllers {
 		data, ok := playerData[playerID]
 		if !ok {
-			// Fallback: send unfiltered state (cached once)
-			if fallbackData == nil {
-				g.filtProjs = g.filtProjs[:0]
-				for _, p := range g.bcastProjs {
-					g.filtProjs = append(g.filtProjs, p.state)
-				}
-				g.filtPlayers = g.filtPlayers[:0]
-				for _, p := range g.bcastPlayers {
-					g.filtPlayers = append(g.filtPlayers, p.state)
-				}
-				g.filtMobs = g.filtMobs[:0]
-				for _, m := range g.bcastMobs {
-					g.filtMobs = append(g.filtMobs, m.state)
-				}
-				g.filtAsteroids = g.filtAsteroids[:0]
-				for _, a := range g.bcastAsteroids {
-					g.filtAsteroids = append(g.filtAsteroids, a.state)
-				}
-				g.filtPickups = g.filtPickups[:0]
-				for _, pk := range g.bcastPickups {
-					g.filtPickups = append(g.filtPickups, pk.state)
-				}
-				st := GameState{
-					Players: g.filtPlayers, Projectiles: g.filtProjs,
-					Mobs: g.filtMobs, Asteroids: g.filtAsteroids,
-					Pickups: g.filtPickups, Tick: g.tick,
-				}
-				var err error
-				fallbackData, err = msgpack.Marshal(&st)
-				if err != nil {
-					continue
-				}
+			if data = full(); data == nil {
+				continue
 			}
-			data = fallbackData
 		}
 		client.SendBinary(data)
 	}
+	for _, client := range g.spectators {
+		if data := full(); data != nil {
+			client.SendBinary(data)
+		}
+	}
 }
 
-// broadcastMsg sends a message to all clients and controllers in the session
+// viewOf returns the player whose viewport p is sent: p itself, or while p
+// is dead in a spectate-on-death session, a living player to follow
+func (g *Game) viewOf(p *Player) *Player {
+	if p.Alive || !g.config.SpectateOnDeath {
+		return p
+	}
+	if f, ok := g.players[p.Following]; ok && f.Alive {
+		return f
+	}
+	p.Following = ""
+	if k, ok := g.players[p.KilledBy]; ok && k.Alive {
+		p.Following = k.ID
+		return k
+	}
+	// Lowest ID keeps the pick stable across frames
+	var pick *Player
+	for _, o := range g.players {
+		if o.Alive && (pick == nil || o.ID < pick.ID) {
+			pick = o
+		}
+	}
+	if pick == nil {
+		return p
+	}
+	p.Following = pick.ID
+	return pick
+}
+
+// marshalFullState encodes the whole world without viewport culling
+func (g *Game) marshalFullState() []byte {
+	g.filtProjs = g.filtProjs[:0]
+	for _, p := range g.bcastProjs {
+		g.filtProjs = append(g.filtProjs, p.state)
+	}
+	g.filtPlayers = g.filtPlayers[:0]
+	for _, p := range g.bcastPlayers {
+		g.filtPlayers = append(g.filtPlayers, p.state)
+	}
+	g.filtMobs = g.filtMobs[:0]
+	for _, m := range g.bcastMobs {
+		g.filtMobs = append(g.filtMobs, m.state)
+	}
+	g.filtAsteroids = g.filtAsteroids[:0]
+	for _, a := range g.bcastAsteroids {
+		g.filtAsteroids = append(g.filtAsteroids, a.state)
+	}
+	g.filtPickups = g.filtPickups[:0]
+	for _, pk := range g.bcastPickups {
+		g.filtPickups = append(g.filtPickups, pk.state)
+	}
+	st := GameState{
+		Players: g.filtPlayers, Projectiles: g.filtProjs,
+		Mobs: g.filtMobs, Asteroids: g.filtAsteroids,
+		Pickups: g.filtPickups, Tick: g.tick,
+		Paused: g.paused,
+	}
+	data, err := msgpack.Marshal(&st)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// broadcastMsg sends a message to all clients, controllers and spectators in the session
 func (g *Game) broadcastMsg(msg Envelope) {
+	if len(g.clients) == 0 && len(g.controllers) == 0 && len(g.spectators) == 0 {
+		return
+	}
 	data, err := json.Marshal(msg)
 	if err != nil {
 		return
@@ -705,13 +1642,27 @@ func (g *Game) broadcastMsg(msg Envelope) {
 	for _, client := range g.controllers {
 		client.SendRaw(data)
 	}
+	for _, client := range g.spectators {
+		client.SendRaw(data)
+	}
+}
+
+// mobSay broadcasts a mob phrase unless chatter is disabled or rate-limited
+func (g *Game) mobSay(mobID, text string) {
+	if text == "" || g.config.DisableMobChatter || g.mobSayCD > 0 {
+		return
+	}
+	g.mobSayCD = g.config.MobChatterCooldown
+	g.broadcastMsg(Envelope{T: MsgMobSay, Data: MobSayMsg{
+		MobID: mobID, Text: text,
+	}})
 }
 
 // checkMobMobCollisions applies soft repulsion between mobs and kills both if relative velocity is high
-func (g *Game) checkMobMobCollisions() {
+func (g *Game) checkMobMobCollisions(dt float64) {
 	// Build a local alive-mob list (can't reuse flatMobs since buildSpatialGrid runs later)
 	mobs := g.flatMobs[:0]
-	for _, m := range g.mobs {
+	for _, m := range g.orderedMobs() {
 		if m.Alive {
 			mobs = append(mobs, m)
 		}
@@ -734,14 +1685,8 @@ func (g *Game) checkMobMobCollisions() {
 				relV := math.Sqrt(rvx*rvx + rvy*rvy)
 				if relV > MobExplodeRelV {
 					// Crash phrases
-					phraseA := pickPhraseAlways("mob_crash")
-					g.broadcastMsg(Envelope{T: MsgMobSay, Data: MobSayMsg{
-						MobID: a.ID, Text: phraseA,
-					}})
-					phraseB := pickPhraseAlways("mob_crash")
-					g.broadcastMsg(Envelope{T: MsgMobSay, Data: MobSayMsg{
-						MobID: b.ID, Text: phraseB,
-					}})
+					g.mobSay(a.ID, pickPhraseAlways("mob_crash"))
+					g.mobSay(b.ID, pickPhraseAlways("mob_crash"))
 					// Both explode
 					a.Alive = false
 					b.Alive = false
@@ -760,10 +1705,10 @@ func (g *Game) checkMobMobCollisions() {
 				nx := dx / dist
 				ny := dy / dist
 				force := MobRepelForce * (1 - dist/repelDist)
-				a.VX -= nx * force * (1.0 / 60.0)
-				a.VY -= ny * force * (1.0 / 60.0)
-				b.VX += nx * force * (1.0 / 60.0)
-				b.VY += ny * force * (1.0 / 60.0)
+				a.VX -= nx * force * dt
+				a.VY -= ny * force * dt
+				b.VX += nx * force * dt
+				b.VY += ny * force * dt
 			}
 		}
 	}
@@ -771,12 +1716,13 @@ func (g *Game) checkMobMobCollisions() {
 
 // checkProjectileMobCollisions checks projectile hits on mobs using spatial grid
 func (g *Game) checkProjectileMobCollisions() {
-	const queryR = ProjectileRadius + SDRadius // use max mob radius for broad-phase
-	for _, proj := range g.flatProjs {
+	for _, proj := range g.projectiles {
 		if !proj.Alive {
 			continue
 		}
-		g.queryBuf = g.grid.QueryBuf(proj.X, proj.Y, queryR, g.queryBuf[:0])
+		qx, qy, queryR := proj.QueryArea(SDRadius) // use max mob radius for broad-phase
+		g.queryBuf = g.grid.QueryBuf(qx, qy, queryR, g.queryBuf[:0])
+		g.countChecks(proj.ID, len(g.queryBuf))
 		nearby := g.queryBuf
 		for _, ref := range nearby {
 			if ref.Kind != 'm' {
@@ -786,9 +1732,12 @@ func (g *Game) checkProjectileMobCollisions() {
 			if !mob.Alive || proj.OwnerID == mob.ID {
 				continue
 			}
-			if CheckCollision(proj.X, proj.Y, ProjectileRadius, mob.X, mob.Y, mob.Radius) {
+			if proj.HitsCircle(mob.X, mob.Y, mob.Radius) {
 				died := mob.TakeDamage(proj.Damage)
 				proj.Alive = false
+				if shooter, ok := g.players[proj.OwnerID]; ok {
+					shooter.RecordHit(proj.Damage)
+				}
 
 				// Broadcast hit event
 				g.broadcastMsg(Envelope{T: MsgHit, Data: HitMsg{
@@ -822,7 +1771,8 @@ func (g *Game) checkAsteroidPlayerCollisions() {
 		if !ast.Alive {
 			continue
 		}
-		g.queryBuf = g.grid.QueryBuf(ast.X, ast.Y, queryR, g.queryBuf[:0])
+		g.queryBuf = g.grid.QueryBufUnique(ast.X, ast.Y, queryR, g.queryBuf[:0])
+		g.countChecks(ast.ID, len(g.queryBuf))
 		for _, ref := range g.queryBuf {
 			if ref.Kind != 'p' {
 				continue
@@ -849,6 +1799,7 @@ func (g *Game) checkAsteroidPlayerCollisions() {
 							KillerID: "asteroid", KillerName: "Asteroid",
 						}})
 					}
+					g.deathBlast(p)
 				}
 			}
 		}
@@ -862,7 +1813,8 @@ func (g *Game) checkAsteroidMobCollisions() {
 		if !ast.Alive {
 			continue
 		}
-		g.queryBuf = g.grid.QueryBuf(ast.X, ast.Y, queryR, g.queryBuf[:0])
+		g.queryBuf = g.grid.QueryBufUnique(ast.X, ast.Y, queryR, g.queryBuf[:0])
+		g.countChecks(ast.ID, len(g.queryBuf))
 		for _, ref := range g.queryBuf {
 			if ref.Kind != 'm' {
 				continue
@@ -873,10 +1825,7 @@ func (g *Game) checkAsteroidMobCollisions() {
 			}
 			if CheckCollision(ast.X, ast.Y, AsteroidRadius, mob.X, mob.Y, mob.Radius) {
 				// Mob phrase before dying
-				phrase := pickPhraseAlways("asteroid_death")
-				g.broadcastMsg(Envelope{T: MsgMobSay, Data: MobSayMsg{
-					MobID: mob.ID, Text: phrase,
-				}})
+				g.mobSay(mob.ID, pickPhraseAlways("asteroid_death"))
 				mob.Alive = false
 				g.broadcastMsg(Envelope{T: MsgKill, Data: KillMsg{
 					KillerID: "asteroid", KillerName: "Asteroid",
@@ -889,12 +1838,13 @@ func (g *Game) checkAsteroidMobCollisions() {
 
 // checkProjectileAsteroidCollisions — projectiles are destroyed by asteroids
 func (g *Game) checkProjectileAsteroidCollisions() {
-	const queryR = ProjectileRadius + AsteroidRadius
-	for _, proj := range g.flatProjs {
+	for _, proj := range g.projectiles {
 		if !proj.Alive {
 			continue
 		}
-		g.queryBuf = g.grid.QueryBuf(proj.X, proj.Y, queryR, g.queryBuf[:0])
+		qx, qy, queryR := proj.QueryArea(AsteroidRadius)
+		g.queryBuf = g.grid.QueryBuf(qx, qy, queryR, g.queryBuf[:0])
+		g.countChecks(proj.ID, len(g.queryBuf))
 		for _, ref := range g.queryBuf {
 			if ref.Kind != 'a' {
 				continue
@@ -903,12 +1853,120 @@ func (g *Game) checkProjectileAsteroidCollisions() {
 			if !ast.Alive {
 				continue
 			}
-			if CheckCollision(proj.X, proj.Y, ProjectileRadius, ast.X, ast.Y, AsteroidRadius) {
+			if proj.HitsCircle(ast.X, ast.Y, AsteroidRadius) {
+				proj.Alive = false
+				break
+			}
+		}
+	}
+}
+
+// placeClear re-rolls (x, y) with roll until a circle of radius r there
+// misses every static asteroid. After staticAsteroidTries it keeps the last
+// roll and leaves the overlap to checkObstacleCollisions to push out.
+func (g *Game) placeClear(x, y *float64, r float64, src randSource, roll func(randSource) (float64, float64)) {
+	for try := 0; try < staticAsteroidTries && g.hitsObstacle(*x, *y, r); try++ {
+		*x, *y = roll(src)
+	}
+}
+
+// hitsObstacle returns true if a circle at (x, y) with radius r overlaps a static asteroid
+func (g *Game) hitsObstacle(x, y, r float64) bool {
+	for i := range g.obstacles {
+		o := &g.obstacles[i]
+		if CheckCollision(x, y, r, o.X, o.Y, o.R) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkObstacleCollisions stops projectiles at static asteroids and keeps
+// ships and drifting asteroids from flying through them
+func (g *Game) checkObstacleCollisions() {
+	if len(g.obstacles) == 0 {
+		return
+	}
+	for _, proj := range g.projectiles {
+		if !proj.Alive {
+			continue
+		}
+		qx, qy, queryR := proj.QueryArea(StaticAsteroidMaxRadius)
+		g.queryBuf = g.grid.QueryBuf(qx, qy, queryR, g.queryBuf[:0])
+		g.countChecks(proj.ID, len(g.queryBuf))
+		for _, ref := range g.queryBuf {
+			if ref.Kind != 'o' {
+				continue
+			}
+			o := &g.obstacles[ref.Idx]
+			if proj.HitsCircle(o.X, o.Y, o.R) {
 				proj.Alive = false
 				break
 			}
 		}
 	}
+
+	for _, p := range g.flatPlayers {
+		g.queryBuf = g.grid.QueryBufUnique(p.X, p.Y, PlayerRadius, g.queryBuf[:0])
+		for _, ref := range g.queryBuf {
+			if ref.Kind == 'o' {
+				g.obstacles[ref.Idx].pushOut(&p.X, &p.Y, &p.VX, &p.VY, PlayerRadius)
+			}
+		}
+	}
+	for _, mob := range g.flatMobs {
+		g.queryBuf = g.grid.QueryBufUnique(mob.X, mob.Y, mob.Radius, g.queryBuf[:0])
+		for _, ref := range g.queryBuf {
+			if ref.Kind == 'o' {
+				g.obstacles[ref.Idx].pushOut(&mob.X, &mob.Y, &mob.VX, &mob.VY, mob.Radius)
+			}
+		}
+	}
+	// Drifting asteroids slide around the field rather than through it
+	for _, ast := range g.flatAsteroids {
+		g.queryBuf = g.grid.QueryBufUnique(ast.X, ast.Y, AsteroidRadius, g.queryBuf[:0])
+		for _, ref := range g.queryBuf {
+			if ref.Kind == 'o' {
+				g.obstacles[ref.Idx].pushOut(&ast.X, &ast.Y, &ast.VX, &ast.VY, AsteroidRadius)
+			}
+		}
+	}
+}
+
+// pullPickups drifts each pickup toward the nearest hurt player within the magnet radius
+func (g *Game) pullPickups(dt float64) {
+	radius := g.config.PickupMagnetRadius
+	if radius <= 0 {
+		return
+	}
+	for _, pk := range g.flatPickups {
+		if !pk.Alive {
+			continue
+		}
+		g.queryBuf = g.grid.QueryBuf(pk.X, pk.Y, radius, g.queryBuf[:0])
+		var nearest *Player
+		best := radius * radius
+		for _, ref := range g.queryBuf {
+			if ref.Kind != 'p' {
+				continue
+			}
+			p := g.flatPlayers[ref.Idx]
+			if !p.Alive || !lowHP(p) {
+				continue
+			}
+			if d2 := (p.X-pk.X)*(p.X-pk.X) + (p.Y-pk.Y)*(p.Y-pk.Y); d2 <= best {
+				nearest, best = p, d2
+			}
+		}
+		if nearest != nil {
+			pk.PullToward(nearest.X, nearest.Y, PickupMagnetSpeed*dt)
+		}
+	}
+}
+
+// pickupHeal is the HP one pickup restores in this session
+func (g *Game) pickupHeal() int {
+	return int(math.Round(PickupHeal * g.config.PickupHealScale))
 }
 
 // checkPlayerPickupCollisions — player picks up health orb
@@ -919,6 +1977,7 @@ func (g *Game) checkPlayerPickupCollisions() {
 			continue
 		}
 		g.queryBuf = g.grid.QueryBuf(pk.X, pk.Y, queryR, g.queryBuf[:0])
+		g.countChecks(pk.ID, len(g.queryBuf))
 		for _, ref := range g.queryBuf {
 			if ref.Kind != 'p' {
 				continue
@@ -929,8 +1988,15 @@ func (g *Game) checkPlayerPickupCollisions() {
 			}
 			if CheckCollision(pk.X, pk.Y, PickupRadius, p.X, p.Y, PlayerRadius) {
 				pk.Alive = false
-				p.HP += PickupHeal
+				if g.config.PickupShieldTime > 0 && lowHP(p) {
+					p.ShieldT = g.config.PickupShieldTime
+					p.ShieldReduction = g.config.PickupShieldReduction
+				}
+				p.HP += g.pickupHeal()
 				if p.HP > p.MaxHP {
+					if cap := float64(g.config.OvershieldCap); cap > 0 {
+						p.Overshield = min(p.Overshield+float64(p.HP-p.MaxHP), cap)
+					}
 					p.HP = p.MaxHP
 				}
 				break
@@ -947,6 +2013,7 @@ func (g *Game) checkPlayerMobCollisions() {
 		}
 		queryR := mob.Radius + PlayerRadius
 		g.queryBuf = g.grid.QueryBuf(mob.X, mob.Y, queryR, g.queryBuf[:0])
+		g.countChecks(mob.ID, len(g.queryBuf))
 		for _, ref := range g.queryBuf {
 			if ref.Kind != 'p' {
 				continue
@@ -986,6 +2053,7 @@ func (g *Game) checkPlayerMobCollisions() {
 							KillerID: mob.ID, KillerName: "Mob",
 						}})
 					}
+					g.deathBlast(p)
 				}
 				break // mob is dead, no need to check more players
 			}
@@ -1000,31 +2068,120 @@ func (g *Game) spawnEntities(dt float64) {
 		return
 	}
 
+	// Over the global budget: keep existing entities but hold off on new
+	// mobs and asteroids until other sessions free some up
+	throttled := g.budget.Exceeded()
+
 	g.mobSpawnCD -= dt
-	if g.mobSpawnCD <= 0 && len(g.mobs) < maxMobsPerSession {
+	if g.mobSpawnCD <= 0 && !throttled && len(g.mobs) < g.mobCap {
 		// Spawn one mob per tick until we reach the cap
-		mob := NewMob()
+		mob := newMobFrom(g.rng, g.config.MobWeights)
+		mob.rng = g.simRng
+		mob.applyDifficulty(MobDifficulty(g.connectedHumans()))
+		mob.Aggression = g.config.MobAggression
+		if g.config.Deterministic {
+			mob.ID = g.entityID(4)
+		}
 		g.mobs[mob.ID] = mob
-		if len(g.mobs) < maxMobsPerSession {
+		if len(g.mobs) < g.mobCap {
 			g.mobSpawnCD = 0.5 // quick respawn to fill back up
 		} else {
-			g.mobSpawnCD = MobSpawnInterval
+			g.mobSpawnCD = g.config.MobSpawnInterval
 		}
 	}
 
 	g.asteroidSpawnCD -= dt
-	if g.asteroidSpawnCD <= 0 && len(g.asteroids) < maxAsteroidsPerSession {
-		ast := NewAsteroid()
+	if g.asteroidSpawnCD <= 0 && !throttled && len(g.asteroids) < g.config.MaxAsteroids {
+		ast := newAsteroidFrom(g.rng)
+		if g.config.Deterministic {
+			ast.ID = g.entityID(4)
+		}
 		g.asteroids[ast.ID] = ast
-		g.asteroidSpawnCD = AsteroidSpawnInterval
+		g.asteroidSpawnCD = g.config.AsteroidSpawnInterval
 	}
 
 	g.pickupSpawnCD -= dt
-	if g.pickupSpawnCD <= 0 && len(g.pickups) < maxPickupsPerSession {
-		pk := NewPickup()
+	if g.pickupSpawnCD <= 0 && len(g.pickups) < g.config.MaxPickups {
+		pk := newPickupFrom(g.rng)
+		g.placeClear(&pk.X, &pk.Y, PickupRadius, g.rng, pickupPoint)
+		if g.config.Deterministic {
+			pk.ID = g.entityID(4)
+		}
 		g.pickups[pk.ID] = pk
-		g.pickupSpawnCD = PickupSpawnInterval
+		g.pickupSpawnCD = g.config.PickupSpawnInterval
+	}
+}
+
+// acquireProjectile returns a pooled projectile (or a new one); callers must reset it
+func (g *Game) acquireProjectile() *Projectile {
+	if n := len(g.projPool); n > 0 {
+		proj := g.projPool[n-1]
+		g.projPool[n-1] = nil
+		g.projPool = g.projPool[:n-1]
+		return proj
+	}
+	return &Projectile{}
+}
+
+// releaseProjectile returns a despawned projectile to the pool
+func (g *Game) releaseProjectile(proj *Projectile) {
+	if len(g.projPool) < maxProjectilesPerSession {
+		g.projPool = append(g.projPool, proj)
+	}
+}
+
+// reportBudget publishes this game's entity count change to the global budget
+func (g *Game) reportBudget() {
+	if g.budget == nil {
+		return
+	}
+	count := len(g.players) + len(g.projectiles) + len(g.mobs) + len(g.asteroids) + len(g.pickups)
+	g.budget.Add(count - g.budgetCount)
+	g.budgetCount = count
+}
+
+// time returns the simulated game time in seconds
+func (g *Game) time() float64 {
+	return float64(g.tick) / TickRate
+}
+
+// Highlights returns the session's fun records, one per award that anyone holds
+func (g *Game) Highlights() []Highlight {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.highlights()
+}
+
+// highlights is Highlights for callers holding the lock. It walks g.players
+// directly: orderedPlayers rewrites a shared slice, which readers under
+// RLock must not do. Ties go to the lower ID.
+func (g *Game) highlights() []Highlight {
+	var longest, damage, double *Player
+	for _, p := range g.players {
+		if p.LongestKill > 0 && (longest == nil || p.LongestKill > longest.LongestKill ||
+			p.LongestKill == longest.LongestKill && p.ID < longest.ID) {
+			longest = p
+		}
+		if p.BestLifeDamage > 0 && (damage == nil || p.BestLifeDamage > damage.BestLifeDamage ||
+			p.BestLifeDamage == damage.BestLifeDamage && p.ID < damage.ID) {
+			damage = p
+		}
+		if p.FastestDouble > 0 && (double == nil || p.FastestDouble < double.FastestDouble ||
+			p.FastestDouble == double.FastestDouble && p.ID < double.ID) {
+			double = p
+		}
+	}
+	var out []Highlight
+	if longest != nil {
+		out = append(out, Highlight{Award: "longest_kill", PlayerID: longest.ID, Name: longest.Name, Value: math.Round(longest.LongestKill)})
+	}
+	if damage != nil {
+		out = append(out, Highlight{Award: "most_life_damage", PlayerID: damage.ID, Name: damage.Name, Value: float64(damage.BestLifeDamage)})
+	}
+	if double != nil {
+		out = append(out, Highlight{Award: "fastest_double", PlayerID: double.ID, Name: double.Name, Value: math.Round(double.FastestDouble*100) / 100})
 	}
+	return out
 }
 
 // playerName returns a player's name or "Unknown"
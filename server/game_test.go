@@ -1,8 +1,14 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 // mockBroadcaster captures sent messages for testing
@@ -117,3 +123,2115 @@ func TestGameProjectileCreation(t *testing.T) {
 		t.Errorf("expected 1 projectile, got %d", projCount)
 	}
 }
+
+func TestGameAccuracyStats(t *testing.T) {
+	g := NewGame()
+	shooter := g.AddPlayer("Shooter")
+	target := g.AddPlayer("Target")
+	target.HP = 1000
+	target.MaxHP = 1000
+
+	// Fire 4 shots: 3 spawned on top of the target, 1 far away
+	for i := 0; i < 4; i++ {
+		shooter.X, shooter.Y = 1000, 1000
+		shooter.VX, shooter.VY = 0, 0
+		shooter.Rotation = 0
+		shooter.TargetR = 0
+		shooter.Firing = true
+		shooter.FireCD = 0
+		if i < 3 {
+			target.X, target.Y = shooter.X+100, shooter.Y
+		} else {
+			target.X, target.Y = 3000, 3000
+		}
+		g.update()
+		shooter.Firing = false
+		for j := 0; j < 5; j++ {
+			g.update()
+		}
+	}
+
+	if shooter.ShotsFired != 4 {
+		t.Fatalf("expected 4 shots fired, got %d", shooter.ShotsFired)
+	}
+	if shooter.ShotsHit != 3 {
+		t.Fatalf("expected 3 shots hit, got %d", shooter.ShotsHit)
+	}
+	if shooter.DamageDealt != 3*ProjectileDamage {
+		t.Errorf("expected %d damage dealt, got %d", 3*ProjectileDamage, shooter.DamageDealt)
+	}
+	if acc := shooter.Accuracy(); acc != 0.75 {
+		t.Errorf("expected accuracy 0.75, got %f", acc)
+	}
+
+	for _, row := range g.scoreboard().Players {
+		if row.ID != shooter.ID {
+			continue
+		}
+		if row.ShotsFired != 4 || row.ShotsHit != 3 || row.DamageDealt != 3*ProjectileDamage || row.Accuracy != 0.75 {
+			t.Errorf("scoreboard should carry the shooting stats, got %+v", row)
+		}
+	}
+}
+
+func countMobSay(m *mockBroadcaster) int {
+	return countMsgType(m, MsgMobSay)
+}
+
+func countMsgType(m *mockBroadcaster, t string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, raw := range m.rawMsgs {
+		if strings.Contains(string(raw), `"t":"`+t+`"`) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestGameMobChatterDisabled(t *testing.T) {
+	g := NewGameWithConfig(MatchConfig{DisableMobChatter: true})
+	p := g.AddPlayer("Listener")
+	mock := &mockBroadcaster{}
+	g.SetClient(p.ID, mock)
+
+	for i := 0; i < 600; i++ {
+		for _, m := range g.mobs {
+			m.PendingPhrase = "chatter"
+		}
+		g.mobSay("mob", "direct")
+		g.update()
+	}
+	if n := countMobSay(mock); n != 0 {
+		t.Errorf("expected no mob phrases with chatter disabled, got %d", n)
+	}
+}
+
+func TestGameMobChatterRateLimited(t *testing.T) {
+	g := NewGame()
+	p := g.AddPlayer("Listener")
+	mock := &mockBroadcaster{}
+	g.SetClient(p.ID, mock)
+
+	// Try to speak every tick for one second
+	for i := 0; i < TickRate; i++ {
+		g.mobSay("mob", "spam")
+		g.update()
+	}
+	want := int(1.0/DefaultMobChatterCooldown) + 1
+	if n := countMobSay(mock); n == 0 || n > want {
+		t.Errorf("expected between 1 and %d mob phrases per second, got %d", want, n)
+	}
+
+	// A cooldown of one tick or less lets every phrase through
+	g = NewGameWithConfig(MatchConfig{MobChatterCooldown: 1.0 / TickRate})
+	p = g.AddPlayer("Listener")
+	mock = &mockBroadcaster{}
+	g.SetClient(p.ID, mock)
+	for i := 0; i < TickRate; i++ {
+		g.mobSay("mob", "spam")
+		g.update()
+	}
+	if n := countMobSay(mock); n != TickRate {
+		t.Errorf("expected a phrase every tick with a one-tick cooldown, got %d", n)
+	}
+}
+
+func TestGameDeathIncludesKillCam(t *testing.T) {
+	g := NewGame()
+	killer := g.AddPlayer("Killer")
+	victim := g.AddPlayer("Victim")
+	mock := &mockBroadcaster{}
+	g.SetClient(victim.ID, mock)
+
+	killer.X, killer.Y = 1000, 1000
+	killer.Rotation, killer.TargetR = 0, 0
+	victim.X, victim.Y = 1100, 1000
+	victim.HP = ProjectileDamage
+
+	// Let some kill-cam frames accumulate before firing
+	for i := 0; i < KillCamSampleEvery*3; i++ {
+		g.update()
+	}
+	victim.X, victim.Y = killer.X+100, killer.Y
+	killer.Firing = true
+	for i := 0; i < 10 && victim.Alive; i++ {
+		g.update()
+	}
+	if victim.Alive {
+		t.Fatal("victim should have died")
+	}
+
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	for _, msg := range mock.messages {
+		env, ok := msg.(Envelope)
+		if !ok || env.T != MsgDeath {
+			continue
+		}
+		death := env.Data.(DeathMsg)
+		if death.KillerID != killer.ID {
+			t.Errorf("expected killer %s, got %s", killer.ID, death.KillerID)
+		}
+		if len(death.Cam) == 0 {
+			t.Fatal("death message should include kill-cam frames")
+		}
+		last := death.Cam[len(death.Cam)-1]
+		if Distance(last.X, last.Y, killer.X, killer.Y) > 50 {
+			t.Errorf("last kill-cam frame (%f,%f) should be near killer (%f,%f)",
+				last.X, last.Y, killer.X, killer.Y)
+		}
+		return
+	}
+	t.Error("victim should have received a death message")
+}
+
+func TestGameMutualKillTrade(t *testing.T) {
+	for run := 0; run < 20; run++ {
+		g := NewGame()
+		a := g.AddPlayer("A")
+		b := g.AddPlayer("B")
+		a.X, a.Y, a.Rotation = 1000, 1000, 0
+		b.X, b.Y, b.Rotation = 1200, 1000, math.Pi
+		a.HP, b.HP = ProjectileDamage, ProjectileDamage
+
+		// Both shots are about to land on the same tick
+		pa := NewProjectile(a)
+		pa.X, pa.Y, pa.VX, pa.VY = b.X-10, b.Y, 0, 0
+		pb := NewProjectile(b)
+		pb.X, pb.Y, pb.VX, pb.VY = a.X+10, a.Y, 0, 0
+		g.projectiles = append(g.projectiles, pa, pb)
+
+		g.update()
+
+		if a.Alive || b.Alive {
+			t.Fatal("both players should die in a trade")
+		}
+		for _, p := range []*Player{a, b} {
+			if p.Kills != 1 || p.Deaths != 1 {
+				t.Fatalf("%s: expected 1 kill and 1 death, got %d/%d", p.Name, p.Kills, p.Deaths)
+			}
+			if p.Score != 1-DeathScorePenalty {
+				t.Fatalf("%s: expected score %d, got %d", p.Name, 1-DeathScorePenalty, p.Score)
+			}
+		}
+	}
+}
+
+func TestGameProjectilesCancelHeadOn(t *testing.T) {
+	shots := func(cfg MatchConfig, sameOwner bool) (*Projectile, *Projectile, *mockBroadcaster) {
+		g := NewGameWithConfig(cfg)
+		a := g.AddPlayer("A")
+		b := g.AddPlayer("B")
+		a.X, a.Y, a.Rotation = 500, 500, 0
+		b.X, b.Y, b.Rotation = 3500, 500, math.Pi
+		mock := &mockBroadcaster{}
+		g.SetClient(a.ID, mock)
+
+		// 20px apart and closing at 2*ProjectileSpeed: they cross mid-tick
+		pa := NewProjectile(a)
+		pa.X, pa.Y, pa.VX, pa.VY = 2000, 2000, ProjectileSpeed, 0
+		pb := NewProjectile(b)
+		if sameOwner {
+			pb = NewProjectile(a)
+		}
+		pb.X, pb.Y, pb.VX, pb.VY = 2020, 2000, -ProjectileSpeed, 0
+		g.projectiles = append(g.projectiles, pa, pb)
+		g.update()
+		return pa, pb, mock
+	}
+
+	pa, pb, mock := shots(MatchConfig{ProjectileCancel: true}, false)
+	if pa.Alive || pb.Alive {
+		t.Error("opposing shots should cancel each other")
+	}
+	if countMsgType(mock, MsgSpark) != 1 {
+		t.Errorf("expected one spark, got %d", countMsgType(mock, MsgSpark))
+	}
+
+	if pa, pb, _ := shots(MatchConfig{ProjectileCancel: true}, true); !pa.Alive || !pb.Alive {
+		t.Error("a player's own shots should not cancel each other")
+	}
+	if pa, pb, _ := shots(MatchConfig{}, false); !pa.Alive || !pb.Alive {
+		t.Error("shots should pass through each other unless cancellation is on")
+	}
+}
+
+func TestGameLongRangeKillHighlight(t *testing.T) {
+	g := NewGame()
+	a := g.AddPlayer("A")
+	b := g.AddPlayer("B")
+	a.X, a.Y = 1000, 1000
+	b.X, b.Y = 1900, 1000
+	b.HP = ProjectileDamage
+
+	// A's shot is about to land on B, 900px away
+	pa := NewProjectile(a)
+	pa.X, pa.Y, pa.VX, pa.VY = b.X-10, b.Y, 0, 0
+	g.projectiles = append(g.projectiles, pa)
+
+	g.update()
+
+	if b.Alive {
+		t.Fatal("B should have been killed")
+	}
+	if math.Abs(a.LongestKill-900) > 20 {
+		t.Fatalf("expected longest kill ~900px, got %f", a.LongestKill)
+	}
+	hs := g.Highlights()
+	if len(hs) == 0 || hs[0].Award != "longest_kill" || hs[0].PlayerID != a.ID {
+		t.Fatalf("expected A to hold the longest_kill highlight, got %+v", hs)
+	}
+	if board := g.scoreboard(); len(board.Highlights) == 0 || board.Highlights[0] != hs[0] {
+		t.Errorf("scoreboard should carry the highlights, got %+v", board.Highlights)
+	}
+}
+
+func TestGamePickupMagnetPullsHurtPlayersOnly(t *testing.T) {
+	for _, tc := range []struct {
+		hp   int
+		pull bool
+	}{{30, true}, {PlayerMaxHP, false}} {
+		g := NewGameWithConfig(MatchConfig{PickupMagnetRadius: 200})
+		p := g.AddPlayer("P")
+		p.X, p.Y, p.HP = 1150, 1000, tc.hp
+		p.TargetX, p.TargetY = p.X, p.Y // hold still
+		pk := &Pickup{ID: "k", X: 1000, Y: 1000, Life: PickupTimeout, Alive: true}
+		g.pickups[pk.ID] = pk
+
+		g.update()
+
+		if moved := pk.X > 1000; moved != tc.pull {
+			t.Errorf("hp %d: pickup pulled=%v, want %v (x=%f)", tc.hp, moved, tc.pull, pk.X)
+		}
+	}
+}
+
+func TestGamePickupHealScale(t *testing.T) {
+	heal := func(cfg MatchConfig) int {
+		g := NewGameWithConfig(cfg)
+		p := g.AddPlayer("P")
+		p.X, p.Y, p.HP = 1000, 1000, 10
+		p.TargetX, p.TargetY = p.X, p.Y
+		g.pickups["k"] = &Pickup{ID: "k", X: p.X, Y: p.Y, Life: PickupTimeout, Alive: true}
+		g.update()
+		return p.HP - 10
+	}
+	if got := heal(MatchConfig{}); got != PickupHeal {
+		t.Errorf("default pickup should heal %d, got %d", PickupHeal, got)
+	}
+	if got := heal(MatchConfig{PickupHealScale: 2}); got != 2*PickupHeal {
+		t.Errorf("scaled pickup should heal %d, got %d", 2*PickupHeal, got)
+	}
+}
+
+func TestGamePickupShieldReducesDamage(t *testing.T) {
+	g := NewGameWithConfig(MatchConfig{PickupShieldTime: 2, PickupShieldReduction: 0.5})
+	p := g.AddPlayer("P")
+	p.X, p.Y, p.HP = 1000, 1000, 10
+	p.TargetX, p.TargetY = p.X, p.Y
+	g.pickups["k"] = &Pickup{ID: "k", X: p.X, Y: p.Y, Life: PickupTimeout, Alive: true}
+	g.update()
+
+	hp := p.HP
+	p.TakeDamage(20)
+	if got := hp - p.HP; got != 10 {
+		t.Errorf("shielded player should take half damage, took %d", got)
+	}
+
+	for i := 0; i < 3*TickRate; i++ {
+		p.Update(1.0 / TickRate)
+	}
+	hp = p.HP
+	p.TakeDamage(20)
+	if got := hp - p.HP; got != 20 {
+		t.Errorf("shield should have expired, took %d", got)
+	}
+}
+
+func TestGameOvershieldFromOverheal(t *testing.T) {
+	g := NewGameWithConfig(MatchConfig{OvershieldCap: 30})
+	p := g.AddPlayer("P")
+	p.X, p.Y = 1000, 1000
+	p.TargetX, p.TargetY = p.X, p.Y
+	for i := 0; i < 2; i++ {
+		id := fmt.Sprintf("k%d", i)
+		g.pickups[id] = &Pickup{ID: id, X: p.X, Y: p.Y, Life: PickupTimeout, Alive: true}
+		g.update()
+	}
+	if p.HP != PlayerMaxHP || p.Overshield < 29 || p.Overshield > 30 {
+		t.Fatalf("expected full HP and ~30 overshield (capped), got %d/%f", p.HP, p.Overshield)
+	}
+	if os := p.ToState().Overshield; os != 30 {
+		t.Errorf("expected overshield 30 in state, got %d", os)
+	}
+
+	p.Overshield = 10
+	p.TakeDamage(15)
+	if p.Overshield != 0 || p.HP != PlayerMaxHP-5 {
+		t.Errorf("overshield should absorb damage first: os %f hp %d", p.Overshield, p.HP)
+	}
+
+	p.Overshield = 10
+	p.Update(1)
+	if p.Overshield != 10-OvershieldDecay {
+		t.Errorf("overshield should decay by %f/s, got %f", OvershieldDecay, p.Overshield)
+	}
+}
+
+func TestGameSpectateOnDeathFollowsKiller(t *testing.T) {
+	g := NewGameWithConfig(MatchConfig{SpectateOnDeath: true})
+	a := g.AddPlayer("A")
+	b := g.AddPlayer("B")
+	mock := &mockBroadcaster{}
+	g.SetClient(a.ID, mock)
+	place := func() {
+		a.X, a.Y = 200, 200
+		b.X, b.Y = 3800, 3800
+	}
+	lastFrame := func() GameState {
+		g.broadcastState()
+		return lastState(t, mock)
+	}
+	sees := func(gs GameState, id string) bool {
+		for _, ps := range gs.Players {
+			if ps.ID == id {
+				return true
+			}
+		}
+		return false
+	}
+
+	place()
+	a.TakeDamage(a.HP)
+	a.KilledBy = b.ID
+	gs := lastFrame()
+	if gs.Following != b.ID || !sees(gs, b.ID) {
+		t.Fatalf("dead player should get the killer's view, following %q", gs.Following)
+	}
+
+	a.Respawn()
+	place()
+	gs = lastFrame()
+	if gs.Following != "" || sees(gs, b.ID) {
+		t.Errorf("respawned player should be back on their own view, following %q", gs.Following)
+	}
+}
+
+func TestGameDeathBlastCreditsLastDamager(t *testing.T) {
+	g := NewGameWithConfig(MatchConfig{DeathBlastRadius: 150, DeathBlastDamage: 40})
+	shooter := g.AddPlayer("Shooter")
+	victim := g.AddPlayer("Victim")
+	bystander := g.AddPlayer("Bystander")
+	shooter.X, shooter.Y = 1000, 1000
+	victim.X, victim.Y = 1500, 1000
+	bystander.X, bystander.Y = 1500, 1080
+	victim.HP = ProjectileDamage
+	bystander.HP = 30
+	for _, p := range []*Player{shooter, victim, bystander} {
+		p.TargetX, p.TargetY = p.X, p.Y
+	}
+
+	proj := NewProjectile(shooter)
+	proj.X, proj.Y, proj.VX, proj.VY = victim.X-10, victim.Y, 0, 0
+	g.projectiles = append(g.projectiles, proj)
+
+	g.update()
+
+	if victim.Alive || bystander.Alive {
+		t.Fatal("victim's explosion should finish off the adjacent bystander")
+	}
+	if bystander.KilledBy != shooter.ID {
+		t.Errorf("blast kill should be credited to the shooter, got %q", bystander.KilledBy)
+	}
+	if shooter.Kills != 2 || !shooter.Alive {
+		t.Errorf("shooter should have 2 kills and be unharmed, got %d kills alive=%v", shooter.Kills, shooter.Alive)
+	}
+}
+
+func TestGameDeathBlastHitsPointBlankKiller(t *testing.T) {
+	g := NewGameWithConfig(MatchConfig{DeathBlastRadius: 150, DeathBlastDamage: 40})
+	shooter := g.AddPlayer("Shooter")
+	victim := g.AddPlayer("Victim")
+	shooter.X, shooter.Y = 1000, 1000
+	victim.X, victim.Y = 1100, 1000
+	victim.HP = ProjectileDamage
+	shooter.HP = 30
+	for _, p := range []*Player{shooter, victim} {
+		p.TargetX, p.TargetY = p.X, p.Y
+	}
+
+	proj := NewProjectile(shooter)
+	proj.X, proj.Y, proj.VX, proj.VY = victim.X-10, victim.Y, 0, 0
+	g.projectiles = append(g.projectiles, proj)
+
+	g.update()
+
+	if victim.Alive || shooter.Alive {
+		t.Fatal("the shooter should die in their victim's blast at point-blank range")
+	}
+	if shooter.Kills != 1 || shooter.KilledBy == shooter.ID {
+		t.Errorf("dying in the blast shouldn't count as a kill, got %d kills killed by %q", shooter.Kills, shooter.KilledBy)
+	}
+	if want := 1 - DeathScorePenalty; shooter.Score != want {
+		t.Errorf("expected score %d after the kill and the death, got %d", want, shooter.Score)
+	}
+}
+
+func TestGameScoreboardCadence(t *testing.T) {
+	g := NewGameWithConfig(MatchConfig{ScoreboardInterval: 0.25})
+	a := g.AddPlayer("A")
+	b := g.AddPlayer("B")
+	a.Score, a.Kills = 3, 3
+	b.Deaths = 2
+	mock := &mockBroadcaster{}
+	g.SetClient(a.ID, mock)
+
+	for i := 0; i < TickRate; i++ {
+		g.update()
+	}
+
+	var boards []ScoreboardMsg
+	mock.mu.Lock()
+	for _, raw := range mock.rawMsgs {
+		var env struct {
+			T string        `json:"t"`
+			D ScoreboardMsg `json:"d"`
+		}
+		if json.Unmarshal(raw, &env) == nil && env.T == MsgScoreboard {
+			boards = append(boards, env.D)
+		}
+	}
+	mock.mu.Unlock()
+
+	if len(boards) != 4 {
+		t.Fatalf("expected 4 scoreboards in one second at 4Hz, got %d", len(boards))
+	}
+	rows := boards[len(boards)-1].Players
+	if len(rows) != 2 || rows[0].ID != a.ID || rows[0].Kills != 3 || rows[1].Deaths != 2 {
+		t.Errorf("unexpected scoreboard rows: %+v", rows)
+	}
+}
+
+func TestGameScoreboardTiebreak(t *testing.T) {
+	g := NewGame()
+	set := func(name string, score, kills, deaths int) *Player {
+		p := g.AddPlayer(name)
+		p.Score, p.Kills, p.Deaths = score, kills, deaths
+		return p
+	}
+	low := set("Low", 1, 9, 0)
+	fewerKills := set("FewerKills", 5, 2, 0)
+	moreDeaths := set("MoreDeaths", 5, 4, 3)
+	top := set("Top", 5, 4, 1)
+	tieB := set("TieB", 0, 0, 0)
+	tieA := set("TieA", 0, 0, 0)
+	if tieA.ID < tieB.ID {
+		tieA, tieB = tieB, tieA
+	}
+
+	want := []*Player{top, moreDeaths, fewerKills, low, tieB, tieA}
+	for run := 0; run < 5; run++ {
+		rows := g.scoreboard().Players
+		for i, p := range want {
+			if rows[i].ID != p.ID {
+				t.Fatalf("rank %d: expected %s, got %s (%+v)", i+1, p.Name, rows[i].Name, rows)
+			}
+		}
+	}
+}
+
+func TestGameDisconnectedPlayerFrozen(t *testing.T) {
+	g := NewGame()
+	p := g.AddPlayer("Dropped")
+	p.Firing = true
+	seq := g.DisconnectPlayer(p.ID)
+
+	for i := 0; i < TickRate; i++ {
+		g.update()
+	}
+	if len(g.projectiles) != 0 {
+		t.Errorf("disconnected player should not fire, got %d projectiles", len(g.projectiles))
+	}
+	if g.PlayerCount() != 1 {
+		t.Fatal("disconnected player should stay in the world during the grace")
+	}
+
+	if g.Rejoin(p.RejoinToken, &mockBroadcaster{}) != p || p.Disconnected {
+		t.Fatal("rejoin with the token should reclaim the ship")
+	}
+	if g.RemoveIfDisconnected(p.ID, seq) {
+		t.Error("a stale grace timer must not remove a rejoined player")
+	}
+}
+
+func TestGameSpectatorGetsUnculledState(t *testing.T) {
+	g := NewGame()
+	a := g.AddPlayer("A")
+	b := g.AddPlayer("B")
+	a.X, a.Y = 200, 200
+	b.X, b.Y = 3800, 3800
+	player, spec := &mockBroadcaster{}, &mockBroadcaster{}
+	g.SetClient(a.ID, player)
+	id := g.AddSpectator(spec)
+
+	if g.PlayerCount() != 2 || g.SpectatorCount() != 1 {
+		t.Fatalf("expected 2 players and 1 spectator, got %d/%d", g.PlayerCount(), g.SpectatorCount())
+	}
+
+	g.broadcastState()
+
+	decode := func(m *mockBroadcaster) GameState {
+		frames := allStates(t, m)
+		if len(frames) != 1 {
+			t.Fatalf("expected 1 frame, got %d", len(frames))
+		}
+		return frames[0]
+	}
+	if n := len(decode(player).Players); n != 1 {
+		t.Errorf("player's frame should cull the far ship, got %d players", n)
+	}
+	if n := len(decode(spec).Players); n != 2 {
+		t.Errorf("spectator's frame should include every ship, got %d players", n)
+	}
+
+	g.RemoveSpectator(id)
+	if g.SpectatorCount() != 0 {
+		t.Error("spectator should be removed")
+	}
+}
+
+func TestGameGlobalBudgetHaltsSpawns(t *testing.T) {
+	budget := NewEntityBudget(5)
+	g := NewGame()
+	g.budget = budget
+	g.AddPlayer("Solo")
+
+	// Spawn a mob while under budget
+	g.mobSpawnCD = 0
+	g.update()
+	if len(g.mobs) != 1 {
+		t.Fatalf("expected 1 mob under budget, got %d", len(g.mobs))
+	}
+	if budget.Total() != 2 {
+		t.Fatalf("expected budget total 2, got %d", budget.Total())
+	}
+
+	// Another session eats the rest of the budget
+	budget.Add(10)
+	g.mobSpawnCD = 0
+	g.asteroidSpawnCD = 0
+	g.update()
+	if len(g.mobs) != 1 {
+		t.Errorf("mob spawning should pause over budget, got %d mobs", len(g.mobs))
+	}
+	if len(g.asteroids) != 0 {
+		t.Errorf("asteroid spawning should pause over budget, got %d", len(g.asteroids))
+	}
+
+	g.Stop()
+	if budget.Total() != 10 {
+		t.Errorf("stopping a game should release its entities, total=%d", budget.Total())
+	}
+}
+
+func TestGameConfigurableSpawns(t *testing.T) {
+	g := NewGameWithConfig(MatchConfig{MaxPickups: 2, PickupSpawnInterval: 2, MaxAsteroids: 1, MaxMobs: 6})
+	for i := 0; i < 20; i++ {
+		g.AddPlayer("Pilot")
+	}
+	if _, mobs := g.Caps(); mobs != 6 {
+		t.Errorf("mob cap should stop at MaxMobs, got %d", mobs)
+	}
+	for i := 0; i < 5; i++ {
+		g.pickupSpawnCD, g.asteroidSpawnCD = 0, 0
+		g.spawnEntities(0)
+	}
+	if len(g.pickups) != 2 || len(g.asteroids) != 1 {
+		t.Errorf("expected caps of 2 pickups and 1 asteroid, got %d and %d", len(g.pickups), len(g.asteroids))
+	}
+	for id := range g.pickups {
+		delete(g.pickups, id)
+		break
+	}
+	g.pickupSpawnCD = 0
+	g.spawnEntities(0)
+	if g.pickupSpawnCD != 2 {
+		t.Errorf("pickup timer should reset to the configured interval, got %v", g.pickupSpawnCD)
+	}
+
+	def := NewGame().config
+	if def.MobSpawnInterval != MobSpawnInterval || def.MaxMobs != maxMobsPerSession ||
+		def.AsteroidSpawnInterval != AsteroidSpawnInterval || def.MaxPickups != maxPickupsPerSession {
+		t.Errorf("unset spawn settings should keep the package defaults, got %+v", def)
+	}
+	c := MatchConfig{MobSpawnInterval: 0.01, MaxAsteroids: 1000}.Sanitize()
+	if c.MobSpawnInterval != MinSpawnInterval || c.MaxAsteroids != asteroidCapLimit {
+		t.Errorf("spawn settings should be clamped, got interval %v cap %d", c.MobSpawnInterval, c.MaxAsteroids)
+	}
+}
+
+// BenchmarkGameTickCombat runs physics ticks with every player firing
+func BenchmarkGameTickCombat(b *testing.B) {
+	g := NewGame()
+	for i := 0; i < maxPlayersPerSession; i++ {
+		p := g.AddPlayer("Bench")
+		p.HP, p.MaxHP = 1<<30, 1<<30
+		p.Firing = true
+		p.TargetR = float64(i)
+	}
+	for i := 0; i < 300; i++ {
+		g.update()
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.update()
+	}
+}
+
+// discardBroadcaster is a client that drops everything, for benchmarks
+type discardBroadcaster struct{ sent int }
+
+func (d *discardBroadcaster) SendJSON(msg interface{}) { d.sent++ }
+func (d *discardBroadcaster) SendRaw(data []byte)      { d.sent++ }
+func (d *discardBroadcaster) SendBinary(data []byte)   { d.sent++ }
+
+// BenchmarkFullSession runs a full session: 20 players steering and firing
+// from synthetic input, mobs, asteroids and pickups, and state broadcasts to
+// every client. One op is one tick.
+func BenchmarkFullSession(b *testing.B) {
+	g := NewGame()
+	var players []*Player
+	for i := 0; i < maxPlayersPerSession; i++ {
+		p := g.AddPlayer("Bench")
+		p.HP, p.MaxHP = 1<<30, 1<<30
+		g.SetClient(p.ID, &discardBroadcaster{})
+		players = append(players, p)
+	}
+	for i := 0; i < maxMobsPerSession; i++ {
+		m := NewMob()
+		m.HP, m.MaxHP = 1<<30, 1<<30
+		g.mobs[m.ID] = m
+	}
+	for i := 0; i < maxAsteroidsPerSession; i++ {
+		a := NewAsteroid()
+		g.asteroids[a.ID] = a
+	}
+	for i := 0; i < maxPickupsPerSession; i++ {
+		pk := NewPickup()
+		g.pickups[pk.ID] = pk
+	}
+
+	// Each player circles a point near its ship, firing in bursts
+	input := func(tick int) {
+		for i, p := range players {
+			a := float64(tick+i*17) * 0.05
+			g.HandleInput(p.ID, ClientInput{
+				MX:     p.X + math.Cos(a)*300,
+				MY:     p.Y + math.Sin(a)*300,
+				Fire:   (tick/30+i)%3 != 0,
+				Boost:  (tick/90+i)%4 == 0,
+				Thresh: 200,
+			})
+		}
+	}
+	for i := 0; i < 300; i++ {
+		input(i)
+		g.update()
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		input(300 + i)
+		g.update()
+	}
+}
+
+func TestGameProjectileCompaction(t *testing.T) {
+	g := NewGame()
+	owner := &Player{ID: "owner", X: 1000, Y: 1000}
+	var keep []*Projectile
+	for i := 0; i < 6; i++ {
+		proj := NewProjectile(owner)
+		proj.VX, proj.VY = 0, 0
+		if i%2 == 0 {
+			proj.Alive = false
+		} else {
+			keep = append(keep, proj)
+		}
+		g.projectiles = append(g.projectiles, proj)
+	}
+
+	g.update()
+
+	if len(g.projectiles) != len(keep) {
+		t.Fatalf("expected %d live projectiles, got %d", len(keep), len(g.projectiles))
+	}
+	for _, want := range keep {
+		found := false
+		for _, proj := range g.projectiles {
+			if proj == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("live projectile %s was dropped", want.ID)
+		}
+	}
+	if len(g.projPool) != 3 {
+		t.Errorf("expected 3 dead projectiles in the pool, got %d", len(g.projPool))
+	}
+	// Spatial grid indices must point at the same projectiles
+	for idx, proj := range g.projectiles {
+		found := false
+		for _, ref := range g.grid.Query(proj.X, proj.Y, 1) {
+			if ref.Kind == 'r' && ref.Idx == idx {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("projectile %d not indexed in grid", idx)
+		}
+	}
+}
+
+func TestGameProjectileKeepsSlotUntilNextTick(t *testing.T) {
+	g := NewGame()
+	target := g.AddPlayer("Target")
+	target.X, target.Y = 2000, 2000
+	owner := &Player{ID: "owner", X: 1000, Y: 1000}
+	var shots []*Projectile
+	for i := 0; i < 3; i++ {
+		proj := NewProjectile(owner)
+		proj.X, proj.Y, proj.VX, proj.VY = 1000+float64(i)*100, 1000, 0, 0
+		shots = append(shots, proj)
+	}
+	hit := shots[1]
+	hit.X, hit.Y = target.X, target.Y // hits this tick
+	// buildSpatialGrid sorts by ID before indexing
+	sort.Slice(shots, func(i, j int) bool { return shots[i].ID < shots[j].ID })
+	g.projectiles = append(g.projectiles, shots...)
+
+	g.update()
+
+	// The hit is only marked dead; the grid built this tick still indexes
+	// the same slots
+	if hit.Alive {
+		t.Fatal("shot on the target should have hit")
+	}
+	for i, proj := range shots {
+		if g.projectiles[i] != proj {
+			t.Errorf("slot %d moved during the collision pass", i)
+		}
+	}
+
+	g.update()
+	if len(g.projectiles) != 2 {
+		t.Fatalf("the dead shot should be compacted out on the next tick, got %d left", len(g.projectiles))
+	}
+	for _, proj := range g.projectiles {
+		if proj == hit {
+			t.Error("the dead shot is still in the slice")
+		}
+	}
+}
+
+func countBroadcasts(m *mockBroadcaster) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.rawMsgs)
+}
+
+func TestGameAdaptiveBroadcastRate(t *testing.T) {
+	calm := NewGame()
+	cp := calm.AddPlayer("Calm")
+	calmMock := &mockBroadcaster{}
+	calm.SetClient(cp.ID, calmMock)
+
+	combat := NewGame()
+	fp := combat.AddPlayer("Shooter")
+	fp.Firing = true
+	combatMock := &mockBroadcaster{}
+	combat.SetClient(fp.ID, combatMock)
+
+	idle := NewGame()
+	ip := idle.AddPlayer("Dead")
+	ip.Alive = false
+	ip.RespawnT = 100
+	idleMock := &mockBroadcaster{}
+	idle.SetClient(ip.ID, idleMock)
+
+	for i := 0; i < TickRate; i++ {
+		calm.update()
+		combat.update()
+		idle.update()
+	}
+
+	nCalm, nCombat, nIdle := countBroadcasts(calmMock), countBroadcasts(combatMock), countBroadcasts(idleMock)
+	if nCombat < BroadcastRate-1 {
+		t.Errorf("combat session should broadcast ~%dHz, got %d", BroadcastRate, nCombat)
+	}
+	if nCalm >= nCombat {
+		t.Errorf("calm session should broadcast less than combat: %d vs %d", nCalm, nCombat)
+	}
+	if nIdle >= nCalm {
+		t.Errorf("idle session should broadcast less than calm: %d vs %d", nIdle, nCalm)
+	}
+	if nIdle < IdleBroadcastRate-1 {
+		t.Errorf("idle session should still broadcast at the minimum rate, got %d", nIdle)
+	}
+}
+
+func TestGameBroadcastRateFollowsNearbyMotion(t *testing.T) {
+	// b cruises past a at (bx, by); a never shoots
+	run := func(bx, by float64) int {
+		g := NewGameWithConfig(MatchConfig{MobSpawnInterval: 1000})
+		a, b := g.AddPlayer("A"), g.AddPlayer("B")
+		a.X, a.Y = 1000, 1000
+		mock := &mockBroadcaster{}
+		g.SetClient(a.ID, mock)
+		for i := 0; i < TickRate; i++ {
+			b.X, b.Y = bx, by
+			b.VX, b.VY = PlayerMaxSpeed/2, 0
+			g.update()
+		}
+		return countBroadcasts(mock)
+	}
+
+	dogfight := run(1200, 1000)
+	distant := run(3500, 3500)
+	if dogfight < BroadcastRate-1 {
+		t.Errorf("ships moving near a player should get the full rate, got %d", dogfight)
+	}
+	if distant >= dogfight {
+		t.Errorf("players alone in quiet space should get a lower rate: %d vs %d", distant, dogfight)
+	}
+}
+
+func TestGameTieredUpdatesThrottleFarEntities(t *testing.T) {
+	g := NewGame()
+	p := g.AddPlayer("Viewer")
+	p.X, p.Y = 1000, 1000
+	mock := &mockBroadcaster{}
+	g.SetClient(p.ID, mock)
+	g.SetTieredUpdates(p.ID, true)
+
+	near := NewTieMob()
+	near.X, near.Y = 1200, 1000
+	far := NewTieMob()
+	far.X, far.Y = 1000+MidTierDist+100, 1000
+	g.mobs[near.ID] = near
+	g.mobs[far.ID] = far
+
+	const broadcasts = 8
+	for i := 0; i < broadcasts; i++ {
+		g.broadcastState()
+	}
+
+	nearSeen, farSeen := 0, 0
+	for _, gs := range allStates(t, mock) {
+		if gs.Tiers&TierNear == 0 {
+			t.Error("tiered frames should always include the near tier")
+		}
+		for _, m := range gs.Mobs {
+			switch m.ID {
+			case near.ID:
+				nearSeen++
+			case far.ID:
+				farSeen++
+			}
+		}
+	}
+	if nearSeen != broadcasts {
+		t.Errorf("near mob should be in every frame, got %d/%d", nearSeen, broadcasts)
+	}
+	if farSeen != broadcasts/FarTierEvery {
+		t.Errorf("far mob should be in every %dth frame, got %d/%d", FarTierEvery, farSeen, broadcasts)
+	}
+}
+
+func TestGameTieredClientGetsVelocityChangedWhileSkipped(t *testing.T) {
+	g := NewGame()
+	p := g.AddPlayer("Viewer")
+	p.X, p.Y = 1000, 1000
+	mock := &mockBroadcaster{}
+	g.SetClient(p.ID, mock)
+	g.SetTieredUpdates(p.ID, true)
+
+	mid := NewTieMob()
+	mid.X, mid.Y = 1000+(NearTierDist+MidTierDist)/2, 1000
+	mid.VX, mid.VY = 0, 0
+	g.mobs[mid.ID] = mid
+
+	g.broadcastState() // mid tier skipped
+	g.broadcastState() // mid tier sent, velocity (0, 0)
+	mid.VX = 120
+	g.broadcastState() // skipped: the change goes out, but not to this client
+	if st := lastState(t, mock); len(st.Mobs) != 0 {
+		t.Fatalf("expected the mid-tier mob to be skipped, got %+v", st.Mobs)
+	}
+	g.broadcastState()
+	st := lastState(t, mock)
+	if len(st.Mobs) != 1 || st.Mobs[0].VX == nil || *st.Mobs[0].VX != 120 {
+		t.Errorf("mob back in a tiered frame should carry the velocity that changed while it was skipped, got %+v", st.Mobs)
+	}
+}
+
+func TestGameUntieredClientGetsEveryVisibleEntity(t *testing.T) {
+	g := NewGame()
+	p := g.AddPlayer("Viewer")
+	p.X, p.Y = 1000, 1000
+	mock := &mockBroadcaster{}
+	g.SetClient(p.ID, mock)
+
+	far := NewTieMob()
+	far.X, far.Y = 1000+MidTierDist+100, 1000
+	g.mobs[far.ID] = far
+
+	for i := 0; i < 4; i++ {
+		g.broadcastState()
+	}
+	for _, gs := range allStates(t, mock) {
+		if len(gs.Mobs) != 1 || gs.Tiers != 0 {
+			t.Errorf("untiered client should get the far mob every frame, got %d mobs tiers=%d", len(gs.Mobs), gs.Tiers)
+		}
+	}
+}
+
+func TestGamePauseFreezesTimers(t *testing.T) {
+	g := NewGame()
+	host := g.AddPlayer("Host")
+	guest := g.AddPlayer("Guest")
+	mock := &mockBroadcaster{}
+	g.SetClient(guest.ID, mock)
+
+	if g.SetPaused(guest.ID, true) {
+		t.Fatal("only the host should be able to pause")
+	}
+	if !g.SetPaused(host.ID, true) {
+		t.Fatal("host pause was rejected")
+	}
+
+	host.FireCD = 0.5
+	host.Firing = true
+	proj := &Projectile{ID: "p", X: 500, Y: 500, VX: 100, Life: 1, Alive: true}
+	g.projectiles = append(g.projectiles, proj)
+	mobCD, pickupCD := g.mobSpawnCD, g.pickupSpawnCD
+	hostX := host.X
+
+	for i := 0; i < TickRate; i++ {
+		g.update()
+	}
+
+	if host.FireCD != 0.5 {
+		t.Errorf("fire cooldown advanced while paused: %f", host.FireCD)
+	}
+	if g.mobSpawnCD != mobCD || g.pickupSpawnCD != pickupCD {
+		t.Errorf("spawn timers advanced while paused: mob %f->%f pickup %f->%f", mobCD, g.mobSpawnCD, pickupCD, g.pickupSpawnCD)
+	}
+	if proj.Life != 1 || proj.X != 500 {
+		t.Errorf("projectile moved or aged while paused: life=%f x=%f", proj.Life, proj.X)
+	}
+	if host.X != hostX || len(g.projectiles) != 1 {
+		t.Errorf("scene should be frozen (x %f->%f, %d projectiles)", hostX, host.X, len(g.projectiles))
+	}
+
+	// The frozen scene is still broadcast, flagged as paused
+	states := allStates(t, mock)
+	if len(states) == 0 || !states[len(states)-1].Paused {
+		t.Fatalf("expected paused state frames, got %d", len(states))
+	}
+	if pausedEvents := countMsgType(mock, MsgPaused); pausedEvents != 1 {
+		t.Errorf("expected 1 pause event, got %d", pausedEvents)
+	}
+
+	if !g.SetPaused(host.ID, false) {
+		t.Fatal("host resume was rejected")
+	}
+	g.update()
+	if host.FireCD >= 0.5 || g.mobSpawnCD >= mobCD || proj.Life >= 1 {
+		t.Error("timers should advance again after resuming")
+	}
+}
+
+func TestGamePauseHostHandoff(t *testing.T) {
+	g := NewGame()
+	host := g.AddPlayer("Host")
+	guest := g.AddPlayer("Guest")
+	if !g.IsHost(host.ID) || g.IsHost(guest.ID) {
+		t.Fatal("first player to join should be host")
+	}
+	g.SetPaused(host.ID, true)
+
+	g.RemovePlayer(host.ID)
+	if !g.IsHost(guest.ID) {
+		t.Fatal("host should pass to a remaining player")
+	}
+	if !g.Paused() || !g.SetPaused(guest.ID, false) {
+		t.Error("new host should be able to resume the match")
+	}
+
+	g.SetPaused(guest.ID, true)
+	g.RemovePlayer(guest.ID)
+	if g.Paused() {
+		t.Error("an empty session should not stay paused")
+	}
+}
+
+// runDeterministicScenario plays a fixed skirmish and returns the exact bits
+// of every position and velocity at the end
+func runDeterministicScenario(t *testing.T) string {
+	t.Helper()
+	g := NewGameWithConfig(MatchConfig{Deterministic: true})
+	g.mobSpawnCD, g.asteroidSpawnCD, g.pickupSpawnCD = 1e9, 1e9, 1e9
+
+	var players []*Player
+	for i := 0; i < 6; i++ {
+		p := g.AddPlayer("Det")
+		a := float64(i) * math.Pi / 3
+		p.X, p.Y = 1500+math.Cos(a)*250, 1500+math.Sin(a)*250
+		p.Rotation = a + math.Pi
+		players = append(players, p)
+	}
+	for i := 0; i < 3; i++ {
+		ast := &Asteroid{ID: fmt.Sprintf("ast%d", i), X: 1300 + float64(i)*200, Y: 1200, VX: 15, VY: 40, Spin: 0.7, Alive: true}
+		g.asteroids[ast.ID] = ast
+	}
+
+	// Ticks stay under RespawnTime: respawn positions are random
+	for tick := 0; tick < 170; tick++ {
+		for i, p := range players {
+			a := float64(tick)*0.03 + float64(i)
+			g.HandleInput(p.ID, ClientInput{
+				MX: 1500 + math.Cos(a)*120, MY: 1500 + math.Sin(a)*120,
+				Fire: (tick+i*5)%20 < 12, Boost: i%2 == 0, Thresh: 200,
+			})
+		}
+		g.update()
+	}
+
+	var sb strings.Builder
+	bits := func(vs ...float64) {
+		for _, v := range vs {
+			fmt.Fprintf(&sb, "%x,", math.Float64bits(v))
+		}
+	}
+	for _, p := range g.orderedPlayers() {
+		fmt.Fprintf(&sb, "%s:%d:", p.ID, p.HP)
+		bits(p.X, p.Y, p.VX, p.VY, p.Rotation)
+	}
+	for _, proj := range g.projectiles {
+		sb.WriteString(proj.ID + ":")
+		bits(proj.X, proj.Y, proj.VX, proj.VY, proj.Life)
+	}
+	for _, ast := range g.flatAsteroids {
+		sb.WriteString(ast.ID + ":")
+		bits(ast.X, ast.Y, ast.Rotation)
+	}
+	return sb.String()
+}
+
+func TestDeterministicModeBitIdentical(t *testing.T) {
+	first := runDeterministicScenario(t)
+	if !strings.Contains(first, "00000001:") {
+		t.Fatalf("deterministic IDs should come from a counter, got %q", first[:40])
+	}
+	for run := 0; run < 5; run++ {
+		if got := runDeterministicScenario(t); got != first {
+			t.Fatalf("run %d diverged from the first run", run+1)
+		}
+	}
+}
+
+func debugFrames(t *testing.T, m *mockBroadcaster) []DebugStateMsg {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var frames []DebugStateMsg
+	for _, raw := range m.rawMsgs {
+		if !strings.Contains(string(raw), `"t":"`+MsgDebugState+`"`) {
+			continue
+		}
+		var env struct {
+			D DebugStateMsg `json:"d"`
+		}
+		if err := json.Unmarshal(raw, &env); err != nil {
+			t.Fatalf("unmarshal debug state: %v", err)
+		}
+		frames = append(frames, env.D)
+	}
+	return frames
+}
+
+func TestGameDebugFeed(t *testing.T) {
+	g := NewGame()
+	dev := g.AddPlayer("Dev")
+	other := g.AddPlayer("Other")
+	dev.X, dev.Y = 1000, 1000
+	other.X, other.Y = 3000, 3000
+	devMock, otherMock := &mockBroadcaster{}, &mockBroadcaster{}
+	g.SetClient(dev.ID, devMock)
+	g.SetClient(other.ID, otherMock)
+	mob := NewMob()
+	mob.X, mob.Y = 1200, 1000
+	g.mobs[mob.ID] = mob
+	g.projectiles = append(g.projectiles, &Projectile{ID: "r1", OwnerID: "x", X: 900, Y: 900, Life: 1, Alive: true})
+
+	for i := 0; i < 6; i++ {
+		g.update()
+	}
+	if n := len(debugFrames(t, devMock)); n != 0 {
+		t.Fatalf("no debug frames expected before enabling, got %d", n)
+	}
+
+	g.SetDebugFeed(dev.ID, true)
+	for i := 0; i < 6; i++ {
+		g.update()
+	}
+	frames := debugFrames(t, devMock)
+	if len(frames) == 0 {
+		t.Fatal("expected debug frames after enabling")
+	}
+	last := frames[len(frames)-1]
+	if last.CellSize != SpatialCellSize || last.Cols == 0 || len(last.Cells) == 0 {
+		t.Errorf("expected grid occupancy, got cs=%v cols=%d cells=%d", last.CellSize, last.Cols, len(last.Cells))
+	}
+	if last.Checks["r1"] == 0 {
+		t.Errorf("expected collision-check counts for the projectile, got %v", last.Checks)
+	}
+	if target, ok := last.MobTargets[mob.ID]; !ok || target != dev.ID {
+		t.Errorf("expected mob to target %s, got %q (present=%v)", dev.ID, target, ok)
+	}
+	if n := len(debugFrames(t, otherMock)); n != 0 {
+		t.Errorf("clients without the feed must not receive debug frames, got %d", n)
+	}
+
+	g.SetDebugFeed(dev.ID, false)
+	before := len(debugFrames(t, devMock))
+	for i := 0; i < 6; i++ {
+		g.update()
+	}
+	if after := len(debugFrames(t, devMock)); after != before {
+		t.Errorf("debug frames kept coming after disabling: %d -> %d", before, after)
+	}
+	if g.debugChecks != nil {
+		t.Error("check counters should be dropped when nobody is watching")
+	}
+}
+
+func TestGameAdvanceCatchUp(t *testing.T) {
+	g := NewGame()
+	p := g.AddPlayer("Lagged")
+	mock := &mockBroadcaster{}
+	g.SetClient(p.ID, mock)
+
+	// A wakeup 3.5 ticks late runs 3 fixed sub-steps and keeps the half tick
+	if n := g.advance(3*TickDuration + TickDuration/2); n != 3 {
+		t.Fatalf("expected 3 catch-up steps, got %d", n)
+	}
+	if g.tick != 3 {
+		t.Errorf("expected tick 3, got %d", g.tick)
+	}
+	if g.accum != TickDuration/2 {
+		t.Errorf("expected half a tick carried over, got %v", g.accum)
+	}
+	if n := countBroadcasts(mock); n != 1 {
+		t.Errorf("catch-up should broadcast once, on the last sub-step; got %d", n)
+	}
+
+	// The carried half tick completes on the next short wakeup
+	if n := g.advance(TickDuration / 2); n != 1 || g.accum != 0 {
+		t.Errorf("expected 1 step and empty accumulator, got %d steps, accum %v", n, g.accum)
+	}
+	if n := g.advance(TickDuration / 3); n != 0 {
+		t.Errorf("an early wakeup should not step, got %d", n)
+	}
+}
+
+func TestGameAdvanceBoundedCatchUp(t *testing.T) {
+	buf := captureLog(t)
+	g := NewGame()
+	if n := g.advance(time.Second); n != DefaultCatchUpSteps {
+		t.Fatalf("expected catch-up capped at %d steps, got %d", DefaultCatchUpSteps, n)
+	}
+	if g.accum != 0 {
+		t.Errorf("excess time should be dropped, accum=%v", g.accum)
+	}
+	if !strings.Contains(buf.String(), "dropped") {
+		t.Errorf("expected a dropped-time warning, got %q", buf.String())
+	}
+
+	g = NewGameWithConfig(MatchConfig{CatchUpSteps: 12})
+	if n := g.advance(10 * time.Second); n != 12 {
+		t.Errorf("expected configured cap of 12 steps, got %d", n)
+	}
+	if c := (MatchConfig{CatchUpSteps: 1000}).Sanitize(); c.CatchUpSteps != MaxCatchUpSteps {
+		t.Errorf("cap should clamp to %d, got %d", MaxCatchUpSteps, c.CatchUpSteps)
+	}
+}
+
+func TestGameCapsScaleWithPlayers(t *testing.T) {
+	g := NewGame()
+	projCap, mobCap := g.Caps()
+	if projCap != minProjectilesPerSession || mobCap != minMobsPerSession {
+		t.Fatalf("empty game should use minimum caps, got %d/%d", projCap, mobCap)
+	}
+
+	var ids []string
+	prev := projCap
+	for i := 0; i < maxPlayersPerSession; i++ {
+		ids = append(ids, g.AddPlayer("P").ID)
+		projCap, mobCap = g.Caps()
+		if projCap < prev {
+			t.Fatalf("projectile cap shrank on join: %d -> %d", prev, projCap)
+		}
+		if projCap > maxProjectilesPerSession || mobCap > maxMobsPerSession {
+			t.Fatalf("caps exceed maximum with %d players: %d/%d", i+1, projCap, mobCap)
+		}
+		prev = projCap
+	}
+	if projCap != maxProjectilesPerSession || mobCap != maxMobsPerSession {
+		t.Errorf("full lobby should reach maximum caps, got %d/%d", projCap, mobCap)
+	}
+
+	for _, id := range ids[1:] {
+		g.RemovePlayer(id)
+	}
+	projCap, mobCap = g.Caps()
+	if projCap != minProjectilesPerSession+projectilesPerPlayer || mobCap != minMobsPerSession {
+		t.Errorf("caps should shrink back for a single player, got %d/%d", projCap, mobCap)
+	}
+}
+
+func TestGameRaisedMobCapReachable(t *testing.T) {
+	g := NewGameWithConfig(MatchConfig{MaxMobs: 16})
+	if _, mobs := g.Caps(); mobs != 2*minMobsPerSession {
+		t.Errorf("doubling MaxMobs should double the base cap, got %d", mobs)
+	}
+	for i := 0; i < maxPlayersPerSession; i++ {
+		g.AddPlayer("Pilot")
+	}
+	if _, mobs := g.Caps(); mobs != 16 {
+		t.Errorf("full lobby should reach MaxMobs 16, got %d", mobs)
+	}
+}
+
+func TestGameMobSpawnPolicy(t *testing.T) {
+	g := NewGameWithConfig(MatchConfig{MinPlayersForMobs: 2, PlayersPerExtraMob: 1})
+	g.AddPlayer("Solo")
+	for i := 0; i < int(MobSpawnInterval+1)*TickRate; i++ {
+		g.update()
+	}
+	if _, mobCap := g.Caps(); mobCap != 0 || len(g.mobs) != 0 {
+		t.Fatalf("no mobs should spawn below the minimum, got cap %d and %d mobs", mobCap, len(g.mobs))
+	}
+
+	g.AddPlayer("Second")
+	if _, mobCap := g.Caps(); mobCap != minMobsPerSession+2 {
+		t.Errorf("expected mob cap %d with two players, got %d", minMobsPerSession+2, mobCap)
+	}
+	g.mobSpawnCD = 0
+	g.update()
+	if len(g.mobs) == 0 {
+		t.Error("mobs should spawn once the minimum is met")
+	}
+}
+
+func TestGameMobDifficultyCountsConnectedHumans(t *testing.T) {
+	g := NewGameWithConfig(MatchConfig{MobWeights: MobWeights{Tie: 1}})
+	host := g.AddPlayer("Host")
+	for i := 0; i < 4; i++ {
+		if _, err := g.AddBot(host.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		g.DisconnectPlayer(g.AddPlayer(fmt.Sprint("Dropped", i)).ID)
+	}
+	g.mobSpawnCD = 0
+	g.update()
+	for _, m := range g.mobs {
+		if m.MaxHP != TieMaxHP {
+			t.Errorf("bots and dropped players shouldn't raise difficulty, got %d HP", m.MaxHP)
+		}
+	}
+	if len(g.mobs) == 0 {
+		t.Fatal("expected a mob to spawn")
+	}
+}
+
+func TestGameRespawnWaves(t *testing.T) {
+	g := NewGameWithConfig(MatchConfig{RespawnWaveInterval: 5})
+	a := g.AddPlayer("A")
+	b := g.AddPlayer("B")
+	respawnedAt := map[*Player]uint64{}
+
+	a.TakeDamage(a.HP)
+	for i := 0; i < 12*TickRate; i++ {
+		if i == TickRate {
+			b.TakeDamage(b.HP) // dies a second later
+		}
+		g.update()
+		for _, p := range []*Player{a, b} {
+			if p.Alive && respawnedAt[p] == 0 && (p != b || i >= TickRate) {
+				respawnedAt[p] = g.tick
+			}
+		}
+	}
+
+	if respawnedAt[a] == 0 || respawnedAt[a] != respawnedAt[b] {
+		t.Fatalf("both players should respawn on the same wave, got ticks %d and %d", respawnedAt[a], respawnedAt[b])
+	}
+	if secs := float64(respawnedAt[a]) / TickRate; secs < 1+RespawnTime {
+		t.Errorf("wave respawn at %.2fs came before B's respawn delay elapsed", secs)
+	}
+}
+
+func TestGameRespawnSendsTeleport(t *testing.T) {
+	g := NewGame()
+	p := g.AddPlayer("P")
+	mock := &mockBroadcaster{}
+	g.SetClient(p.ID, mock)
+	p.TakeDamage(p.HP)
+
+	for i := 0; i <= int(RespawnTime*TickRate)+1 && !p.Alive; i++ {
+		g.update()
+	}
+	if !p.Alive {
+		t.Fatal("player should have respawned")
+	}
+
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	for _, raw := range mock.rawMsgs {
+		var env struct {
+			T string      `json:"t"`
+			D TeleportMsg `json:"d"`
+		}
+		if json.Unmarshal(raw, &env) != nil || env.T != MsgTeleport {
+			continue
+		}
+		if env.D.ID != p.ID || env.D.X != round1(p.X) || env.D.Y != round1(p.Y) {
+			t.Errorf("teleport should carry the respawn point, got %+v want (%f,%f)", env.D, p.X, p.Y)
+		}
+		return
+	}
+	t.Error("respawn should broadcast a teleport event")
+}
+
+func TestGameKillStreaks(t *testing.T) {
+	g := NewGame()
+	killer := g.AddPlayer("Killer")
+	mock := &mockBroadcaster{}
+	g.SetClient(killer.ID, mock)
+
+	for i := 0; i < 5; i++ {
+		victim := g.AddPlayer("Victim")
+		victim.TakeDamage(victim.HP)
+		g.creditPlayerKill(killer, victim)
+		g.RemovePlayer(victim.ID)
+	}
+
+	if killer.Streak != 5 {
+		t.Fatalf("expected a 5-kill streak, got %d", killer.Streak)
+	}
+	if want := 5 + StreakBonusScore; killer.Score != want {
+		t.Errorf("5th kill should score the streak bonus: want %d, got %d", want, killer.Score)
+	}
+	var tiers []string
+	mock.mu.Lock()
+	for _, raw := range mock.rawMsgs {
+		var env struct {
+			T string    `json:"t"`
+			D StreakMsg `json:"d"`
+		}
+		if json.Unmarshal(raw, &env) == nil && env.T == MsgStreak {
+			tiers = append(tiers, env.D.Tier)
+		}
+	}
+	mock.mu.Unlock()
+	if len(tiers) != 2 || tiers[0] != "killing_spree" || tiers[1] != "rampage" {
+		t.Errorf("expected spree and rampage announcements, got %v", tiers)
+	}
+
+	killer.TakeDamage(killer.HP)
+	if killer.Streak != 0 {
+		t.Errorf("death should reset the streak, got %d", killer.Streak)
+	}
+}
+
+func TestGameWrapFlag(t *testing.T) {
+	g := NewGame()
+	p := g.AddPlayer("Edge")
+	mock := &mockBroadcaster{}
+	g.SetClient(p.ID, mock)
+	lastFrame := func() PlayerState {
+		g.broadcastState()
+		gs := lastState(t, mock)
+		if len(gs.Players) != 1 {
+			t.Fatalf("expected 1 player, got %d", len(gs.Players))
+		}
+		return gs.Players[0]
+	}
+
+	p.X, p.Y = WorldWidth-1, 1000
+	p.VX, p.VY = 200, 0
+	p.Update(1.0 / 60)
+	if p.X > 10 {
+		t.Fatalf("expected the ship to wrap to the left edge, x=%.1f", p.X)
+	}
+	if !lastFrame().Wrap {
+		t.Error("frame after an edge wrap should carry the wrap flag")
+	}
+	p.Update(1.0 / 60)
+	if lastFrame().Wrap {
+		t.Error("wrap flag should clear once broadcast")
+	}
+}
+
+func TestGameWrapFlagReachesTieredClient(t *testing.T) {
+	g := NewGame()
+	p := g.AddPlayer("Viewer")
+	p.X, p.Y = 700, 1000
+	mock := &mockBroadcaster{}
+	g.SetClient(p.ID, mock)
+	g.SetTieredUpdates(p.ID, true)
+
+	// A mid-tier mob comes over the left edge on a frame that skips mid
+	mob := NewTieMob()
+	mob.X, mob.Y, mob.Wrapped = 2, 1000, true
+	g.mobs[mob.ID] = mob
+	g.broadcastState()
+	st := lastState(t, mock)
+	if st.Tiers&TierMid != 0 {
+		t.Fatal("expected the first tiered frame to skip the mid tier")
+	}
+	if len(st.Mobs) != 1 || !st.Mobs[0].Wrap {
+		t.Errorf("a wrapped mob should go out on the frame it wraps, got %+v", st.Mobs)
+	}
+	g.broadcastState()
+	g.broadcastState()
+	if st := lastState(t, mock); len(st.Mobs) != 0 {
+		t.Errorf("once the flag is sent the mob should follow its tier again, got %+v", st.Mobs)
+	}
+}
+
+func TestGameSkipsBroadcastWithoutClients(t *testing.T) {
+	g := NewGame()
+	p := g.AddPlayer("Disconnected")
+	p.Firing = true
+	for i := 0; i < TickRate; i++ {
+		g.update()
+	}
+	if g.bcastSeq != 0 {
+		t.Fatalf("expected no state marshaling without clients, got %d broadcasts", g.bcastSeq)
+	}
+	if len(g.lastVX) != 0 {
+		t.Errorf("velocity deltas should be reset while nobody watches")
+	}
+
+	mock := &mockBroadcaster{}
+	g.SetClient(p.ID, mock)
+	g.update()
+	if n := countBroadcasts(mock); n != 1 {
+		t.Fatalf("expected a broadcast on the first tick after a client connects, got %d", n)
+	}
+	state := allStates(t, mock)[0]
+	if len(state.Players) != 1 || state.Players[0].VX == nil {
+		t.Error("first frame after reconnect should carry full player velocity")
+	}
+}
+
+func TestGameBroadcastsToControllerOnly(t *testing.T) {
+	g := NewGame()
+	p := g.AddPlayer("Phone")
+	phone := &mockBroadcaster{}
+	g.SetController(p.ID, phone)
+	for i := 0; i < TickRate; i++ {
+		g.update()
+	}
+	if n := countBroadcasts(phone); n == 0 {
+		t.Error("a controller with no linked client should still get state frames")
+	}
+}
+
+func TestGameVelocityDeltaDisabled(t *testing.T) {
+	frames := func(cfg MatchConfig) []GameState {
+		g := NewGameWithConfig(cfg)
+		p := g.AddPlayer("Steady")
+		p.X, p.Y = 2000, 2000
+		mob := NewTieMob()
+		mob.X, mob.Y = 2100, 2000
+		g.mobs[mob.ID] = mob
+		mock := &mockBroadcaster{}
+		g.SetClient(p.ID, mock)
+		for i := 0; i < 3; i++ {
+			g.broadcastState()
+		}
+		return allStates(t, mock)
+	}
+
+	for i, gs := range frames(MatchConfig{DisableVelocityDelta: true}) {
+		if len(gs.Players) != 1 || gs.Players[0].VX == nil || gs.Players[0].VY == nil {
+			t.Errorf("frame %d: player velocity should always be sent", i)
+		}
+		if len(gs.Mobs) != 1 || gs.Mobs[0].VX == nil || gs.Mobs[0].VY == nil {
+			t.Errorf("frame %d: mob velocity should always be sent", i)
+		}
+	}
+	if gs := frames(MatchConfig{})[2]; gs.Players[0].VX != nil {
+		t.Error("unchanged velocity should be omitted by default")
+	}
+}
+
+func TestGameVelocityDeltaThresholds(t *testing.T) {
+	// Ships speed up by 4px/s per frame; counts frames carrying velocity
+	sent := func(cfg MatchConfig) (players, mobs int) {
+		g := NewGameWithConfig(cfg)
+		p := g.AddPlayer("Ramp")
+		p.X, p.Y = 2000, 2000
+		mob := NewTieMob()
+		mob.X, mob.Y = 2100, 2000
+		g.mobs[mob.ID] = mob
+		mock := &mockBroadcaster{}
+		g.SetClient(p.ID, mock)
+		for i := 0; i < 20; i++ {
+			p.VX += 4
+			mob.VX += 4
+			g.broadcastState()
+		}
+		for _, gs := range allStates(t, mock) {
+			if gs.Players[0].VX != nil {
+				players++
+			}
+			if gs.Mobs[0].VX != nil {
+				mobs++
+			}
+		}
+		return players, mobs
+	}
+
+	defP, defM := sent(MatchConfig{})
+	lowP, _ := sent(MatchConfig{PlayerVelDelta: 2})
+	highP, highM := sent(MatchConfig{PlayerVelDelta: 20, MobVelDelta: 20})
+	if lowP <= defP || highP >= defP {
+		t.Errorf("player velocity updates should fall as the threshold rises: 2->%d, default->%d, 20->%d", lowP, defP, highP)
+	}
+	if highM >= defM {
+		t.Errorf("a higher mob threshold should suppress more: default->%d, 20->%d", defM, highM)
+	}
+	if _, m := sent(MatchConfig{PlayerVelDelta: 20}); m != defM {
+		t.Errorf("player threshold should not affect mobs: %d vs %d", m, defM)
+	}
+}
+
+func TestGameProjectileVelocityForLateViewers(t *testing.T) {
+	g := NewGame()
+	shooter := g.AddPlayer("Shooter")
+	shooter.X, shooter.Y, shooter.Rotation = 1000, 1000, 0
+	shooterMock := &mockBroadcaster{}
+	g.SetClient(shooter.ID, shooterMock)
+	proj := NewProjectile(shooter)
+	g.assignProjectileID(proj)
+	g.projectiles = append(g.projectiles, proj)
+	g.broadcastState() // the shooter sees the shot and its velocity
+
+	// The shot flies into the view of a player who wasn't watching it,
+	// and a late joiner connects
+	watcher := g.AddPlayer("Watcher")
+	watcher.X, watcher.Y = proj.X+DefaultCullDist+300, proj.Y
+	watchMock := &mockBroadcaster{}
+	g.SetClient(watcher.ID, watchMock)
+	g.broadcastState()
+	if st := lastState(t, watchMock); len(st.Projectiles) != 0 {
+		t.Fatalf("shot should start out of the watcher's view, got %+v", st.Projectiles)
+	}
+	proj.X += 400
+	late := g.AddPlayer("Late")
+	late.X, late.Y = proj.X, proj.Y+100
+	lateMock := &mockBroadcaster{}
+	g.SetClient(late.ID, lateMock)
+	g.broadcastState()
+
+	for name, m := range map[string]*mockBroadcaster{"watcher": watchMock, "late joiner": lateMock} {
+		st := lastState(t, m)
+		if len(st.Projectiles) != 1 || st.Projectiles[0].VX == nil {
+			t.Errorf("%s should get the shot's velocity the first time it sees it, got %+v", name, st.Projectiles)
+		}
+	}
+	if st := lastState(t, shooterMock); len(st.Projectiles) != 1 || st.Projectiles[0].VX != nil {
+		t.Errorf("the shooter already has the velocity, got %+v", st.Projectiles)
+	}
+}
+
+func TestGameProjectileVelocityInState(t *testing.T) {
+	frames := func(cfg MatchConfig) ([]GameState, *Projectile) {
+		g := NewGameWithConfig(cfg)
+		p := g.AddPlayer("Gunner")
+		p.X, p.Y, p.Rotation = 2000, 2000, 0
+		p.VX, p.VY = 0, 300 // strafing: the shot inherits sideways drift
+		mock := &mockBroadcaster{}
+		g.SetClient(p.ID, mock)
+		proj := NewProjectile(p)
+		g.projectiles = append(g.projectiles, proj)
+		g.broadcastState()
+		g.broadcastState()
+		return allStates(t, mock), proj
+	}
+
+	fs, proj := frames(MatchConfig{})
+	first := fs[0].Projectiles[0]
+	if first.VX == nil || first.VY == nil {
+		t.Fatal("first frame should carry the shot's velocity")
+	}
+	if *first.VX != round1(proj.VX) || *first.VY != round1(proj.VY) || *first.VY == 0 {
+		t.Errorf("expected actual velocity (%.1f,%.1f) including inherited drift, got (%.1f,%.1f)",
+			proj.VX, proj.VY, *first.VX, *first.VY)
+	}
+	if fs[1].Projectiles[0].VX != nil {
+		t.Error("velocity should not be resent for a shot already broadcast")
+	}
+
+	fs, _ = frames(MatchConfig{DisableVelocityDelta: true})
+	if fs[1].Projectiles[0].VX == nil {
+		t.Error("with delta compression off every frame should carry shot velocity")
+	}
+}
+
+func TestGameInputResetsIdle(t *testing.T) {
+	g := NewGameWithConfig(MatchConfig{AFKWarnAfter: 0.5, AFKKickAfter: 1})
+	active := g.AddPlayer("Active")
+	phone := g.AddPlayer("Phone")
+	idle := g.AddPlayer("Idle")
+	for i := 0; i < 2*TickRate; i++ {
+		if i%(TickRate/2) == 0 {
+			g.HandleInput(active.ID, ClientInput{MX: active.X + 100, MY: active.Y})
+			g.SetController(phone.ID, &mockBroadcaster{})
+		}
+		g.update()
+	}
+	if !g.HasPlayer(active.ID) || !g.HasPlayer(phone.ID) {
+		t.Error("input and controller attaches should keep a player from being kicked")
+	}
+	if g.HasPlayer(idle.ID) {
+		t.Error("player without input for AFKKickAfter should be removed")
+	}
+}
+
+func TestGameSpentProjectileNotBroadcast(t *testing.T) {
+	g := NewGame()
+	shooter := g.AddPlayer("Shooter")
+	target := g.AddPlayer("Target")
+	shooter.X, shooter.Y = 1000, 1000
+	target.X, target.Y = 1100, 1000
+	mock := &mockBroadcaster{}
+	g.SetClient(shooter.ID, mock)
+
+	hit := NewProjectile(shooter)
+	hit.X, hit.Y, hit.VX, hit.VY = target.X-5, target.Y, 0, 0
+	miss := NewProjectile(shooter)
+	miss.X, miss.Y, miss.VX, miss.VY = 1000, 1300, 0, 0
+	g.projectiles = append(g.projectiles, hit, miss)
+	g.buildSpatialGrid()
+	g.checkCollisions()
+	if hit.Alive {
+		t.Fatal("projectile on the target should have hit")
+	}
+
+	g.broadcastState()
+	gs := lastState(t, mock)
+	if len(gs.Projectiles) != 1 || gs.Projectiles[0].ID != miss.ID {
+		t.Errorf("only the live projectile should be broadcast, got %+v", gs.Projectiles)
+	}
+}
+
+func TestGameRamGriefingKick(t *testing.T) {
+	run := func(cfg MatchConfig) (*Game, *Player, []*Player, *mockBroadcaster) {
+		g := NewGameWithConfig(cfg)
+		griefer := g.AddPlayer("Griefer")
+		mock := &mockBroadcaster{}
+		g.SetClient(griefer.ID, mock)
+		var victims []*Player
+		for i := 0; i < 3 && g.HasPlayer(griefer.ID); i++ {
+			v := g.AddPlayer("Victim")
+			v.X, v.Y, v.VX, v.VY = 1000, 1000, 0, 0
+			griefer.Respawn()
+			griefer.X, griefer.Y, griefer.VX, griefer.VY = 1000-PlayerRadius, 1000, 300, 0
+			g.buildSpatialGrid()
+			g.checkPlayerCollisions()
+			victims = append(victims, v)
+		}
+		return g, griefer, victims, mock
+	}
+
+	g, griefer, victims, mock := run(MatchConfig{RamKickCount: 3})
+	if g.HasPlayer(griefer.ID) {
+		t.Fatal("three rams within the window should kick the rammer")
+	}
+	kicked := false
+	mock.mu.Lock()
+	for _, m := range mock.messages {
+		if env, ok := m.(Envelope); ok && env.T == MsgKicked {
+			kicked = env.Data.(KickedMsg).Reason == "ramming"
+		}
+	}
+	mock.mu.Unlock()
+	if !kicked {
+		t.Error("griefer should be told they were kicked for ramming")
+	}
+	for _, v := range victims {
+		if !g.HasPlayer(v.ID) {
+			t.Error("stationary ships that were rammed should not be kicked")
+		}
+	}
+
+	if g, griefer, _, _ := run(MatchConfig{}); !g.HasPlayer(griefer.ID) {
+		t.Error("ram kicks should be off by default")
+	}
+}
+
+func TestGameRamKickAfterCollisionPass(t *testing.T) {
+	g := NewGameWithConfig(MatchConfig{Deterministic: true, RamKickCount: 1})
+	griefer, victim := g.AddPlayer("Griefer"), g.AddPlayer("Victim")
+	x, y := g.AddPlayer("X"), g.AddPlayer("Y")
+	griefer.X, griefer.Y, griefer.VX = 1000-PlayerRadius, 1000, 300
+	victim.X, victim.Y = 1000, 1000
+	x.X, x.Y, x.VX = 3000-PlayerRadius, 3000, 300
+	y.X, y.Y = 3000, 3000
+
+	// The griefer's pair comes first; the kick must wait for the other pair
+	var pending []string
+	g.onKick = func(id string) {
+		if x.Alive || y.Alive {
+			pending = append(pending, id)
+		}
+	}
+	g.buildSpatialGrid()
+	g.checkPlayerCollisions()
+
+	if len(pending) != 0 {
+		t.Errorf("rammers %v were kicked before the collision pass finished", pending)
+	}
+	if g.HasPlayer(griefer.ID) || g.HasPlayer(x.ID) {
+		t.Error("both rammers should be kicked once the pass is done")
+	}
+	if !g.HasPlayer(victim.ID) || !g.HasPlayer(y.ID) {
+		t.Error("rammed ships should stay")
+	}
+}
+
+func TestCullDistFor(t *testing.T) {
+	if got := CullDistFor(0, 0); got != DefaultCullDist {
+		t.Errorf("unreported viewport should use the default, got %.0f", got)
+	}
+	if got := CullDistFor(600, 800); got != 500+CullMargin {
+		t.Errorf("600x800 should cull at half the diagonal plus margin, got %.0f", got)
+	}
+	if got := CullDistFor(200, 100); got != MinCullDist {
+		t.Errorf("tiny viewport should clamp to %.0f, got %.0f", MinCullDist, got)
+	}
+	if got := CullDistFor(WorldWidth*4, WorldHeight*4); got != MaxCullDist {
+		t.Errorf("huge viewport should clamp to %.0f, got %.0f", MaxCullDist, got)
+	}
+}
+
+func TestGameViewportCulling(t *testing.T) {
+	g := NewGame()
+	p := g.AddPlayer("Phone")
+	p.X, p.Y = 2000, 2000
+	mob := NewTieMob()
+	mob.X, mob.Y = 2700, 2000
+	g.mobs[mob.ID] = mob
+	mock := &mockBroadcaster{}
+	g.SetClient(p.ID, mock)
+	mobsSeen := func() int {
+		g.broadcastState()
+		return len(lastState(t, mock).Mobs)
+	}
+
+	if mobsSeen() != 1 {
+		t.Fatal("mob 700px away should be in view at the default cull distance")
+	}
+	g.SetViewport(p.ID, 400, 800)
+	if mobsSeen() != 0 {
+		t.Error("mob 700px away should be culled for a phone-sized viewport")
+	}
+}
+
+// spawnFingerprint runs only the spawn timers for a while and describes
+// every PvE entity that appeared
+func spawnFingerprint(g *Game) string {
+	g.AddPlayer("Scrim")
+	for i := 0; i < 30*TickRate; i++ {
+		g.spawnEntities(1.0 / TickRate)
+	}
+	var parts []string
+	for _, m := range g.mobs {
+		parts = append(parts, fmt.Sprintf("m%d:%.3f,%.3f,%.3f", m.ShipType, m.X, m.Y, m.StrafeTimer))
+	}
+	for _, a := range g.asteroids {
+		parts = append(parts, fmt.Sprintf("a:%.3f,%.3f,%.3f,%.3f", a.X, a.Y, a.VX, a.VY))
+	}
+	for _, pk := range g.pickups {
+		parts = append(parts, fmt.Sprintf("k:%.3f,%.3f", pk.X, pk.Y))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
+
+func TestSameSeedSameSpawns(t *testing.T) {
+	a := spawnFingerprint(NewGameWithConfig(MatchConfig{Seed: 4242}))
+	b := spawnFingerprint(NewGameWithConfig(MatchConfig{Seed: 4242}))
+	if a == "" || a != b {
+		t.Fatalf("same seed should spawn identically:\n%s\n%s", a, b)
+	}
+	if c := spawnFingerprint(NewGameWithConfig(MatchConfig{Seed: 4243})); c == a {
+		t.Error("a different seed should spawn differently")
+	}
+}
+
+func TestSetSeedHostOnlyUntilSpawn(t *testing.T) {
+	g := NewGame()
+	if g.Seed() == 0 {
+		t.Fatal("sessions without a seed should get a random one")
+	}
+	host := g.AddPlayer("Host")
+	guest := g.AddPlayer("Guest")
+
+	if err := g.SetSeed(guest.ID, 7); err != ErrNotHost {
+		t.Errorf("expected ErrNotHost, got %v", err)
+	}
+	if err := g.SetSeed(host.ID, 0); err != ErrInvalidSeed {
+		t.Errorf("expected ErrInvalidSeed, got %v", err)
+	}
+	if err := g.SetSeed(host.ID, 7); err != nil || g.Seed() != 7 {
+		t.Fatalf("host should set the seed before spawns: err=%v seed=%d", err, g.Seed())
+	}
+
+	g.update()
+	if err := g.SetSeed(host.ID, 8); err != ErrSeedLocked {
+		t.Errorf("expected seed to lock on the first tick with two humans, got %v", err)
+	}
+
+	// A host waiting alone can still reseed after PvE has spawned; the
+	// early spawns are dropped so the match starts from the new seed
+	solo := NewGame()
+	sh := solo.AddPlayer("Host")
+	solo.pickupSpawnCD = 0
+	solo.update()
+	if len(solo.pickups) == 0 {
+		t.Fatal("expected a pickup to spawn")
+	}
+	if err := solo.SetSeed(sh.ID, 9); err != nil {
+		t.Fatalf("solo host should reseed after a spawn, got %v", err)
+	}
+	if len(solo.pickups) != 0 || solo.pickupSpawnCD != solo.config.PickupSpawnInterval {
+		t.Errorf("reseed should clear early spawns: %d pickups, cd %.1f", len(solo.pickups), solo.pickupSpawnCD)
+	}
+
+	// Reseeding before any spawn gives the same patterns as creating with that seed
+	reseeded := NewGame()
+	h := reseeded.AddPlayer("Host")
+	reseeded.SetSeed(h.ID, 4242)
+	reseeded.RemovePlayer(h.ID)
+	if spawnFingerprint(reseeded) != spawnFingerprint(NewGameWithConfig(MatchConfig{Seed: 4242})) {
+		t.Error("SetSeed should reproduce a created-with-seed session's spawns")
+	}
+}
+
+func TestGameAddBotHostOnly(t *testing.T) {
+	g := NewGame()
+	host := g.AddPlayer("Host")
+	guest := g.AddPlayer("Guest")
+	if _, err := g.AddBot(guest.ID); err != ErrNotHost {
+		t.Fatalf("guest adding a bot: expected ErrNotHost, got %v", err)
+	}
+	if err := g.RemoveBot(host.ID); err != ErrNoBots {
+		t.Fatalf("removing with no bots: expected ErrNoBots, got %v", err)
+	}
+	for i := 2; i < maxPlayersPerSession; i++ {
+		if _, err := g.AddBot(host.ID); err != nil {
+			t.Fatalf("bot %d: %v", i, err)
+		}
+	}
+	if _, err := g.AddBot(host.ID); err != ErrSessionFull {
+		t.Fatalf("expected ErrSessionFull once the session is full, got %v", err)
+	}
+	if n := g.HumanCount(); n != 2 {
+		t.Errorf("bots should not count as humans, got %d", n)
+	}
+
+	// Bots never become host, even when the last human leaves
+	g.RemovePlayer(host.ID)
+	g.RemovePlayer(guest.ID)
+	if g.host != "" {
+		t.Errorf("a bot was handed host: %q", g.host)
+	}
+	if g.HumanCount() != 0 {
+		t.Error("bot-only session should report no humans")
+	}
+}
+
+func TestGameRemoveBotNewestFirst(t *testing.T) {
+	g := NewGame()
+	host := g.AddPlayer("Host")
+	var bots []*Player
+	for i := 0; i < 11; i++ {
+		b, err := g.AddBot(host.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bots = append(bots, b)
+	}
+	if err := g.RemoveBot(host.ID); err != nil {
+		t.Fatal(err)
+	}
+	if g.HasPlayer(bots[10].ID) || !g.HasPlayer(bots[9].ID) {
+		t.Errorf("expected %s (the newest bot) to be removed", bots[10].Name)
+	}
+}
+
+func TestGameEveryBotThinksEachTick(t *testing.T) {
+	g := NewGame()
+	host := g.AddPlayer("Host")
+	var bots []*Player
+	for i := 0; i < 8; i++ {
+		b, err := g.AddBot(host.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Pair the bots up so most of them have a target in range
+		b.X, b.Y = 1000+float64(i/2)*600, 1000+float64(i%2)*200
+		b.HP, b.MaxHP = 1<<30, 1<<30
+		bots = append(bots, b)
+	}
+	for tick := 0; tick < 200; tick++ {
+		for _, b := range bots {
+			b.SlowThresh = -1
+		}
+		g.thinkBots()
+		for _, b := range bots {
+			if b.Alive && b.SlowThresh < 0 {
+				t.Fatalf("tick %d: %s got no input", tick, b.Name)
+			}
+		}
+		g.update()
+	}
+}
+
+func TestGameBotHuntsAndFires(t *testing.T) {
+	g := NewGameWithConfig(MatchConfig{AFKWarnAfter: 0.5, AFKKickAfter: 1})
+	host := g.AddPlayer("Host")
+	host.HP, host.MaxHP = 1<<30, 1<<30
+	bot, err := g.AddBot(host.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	host.X, host.Y = 2000, 2000
+	bot.X, bot.Y, bot.VX, bot.VY = 2000, 2400, 0, 0
+	bot.Rotation = 0
+	for i := 0; i < 2*TickRate; i++ {
+		host.X, host.Y, host.VX, host.VY = 2000, 2000, 0, 0
+		g.HandleInput(host.ID, ClientInput{MX: host.X, MY: host.Y})
+		g.update()
+	}
+	if !g.HasPlayer(bot.ID) {
+		t.Fatal("bots should never be kicked for idling")
+	}
+	if bot.ShotsFired == 0 {
+		t.Error("bot should turn toward and fire at a ship in range")
+	}
+	if d := math.Abs(NormalizeAngle(bot.Rotation - math.Atan2(host.Y-bot.Y, host.X-bot.X))); d > 0.3 {
+		t.Errorf("bot should be facing its target, off by %.2f rad", d)
+	}
+}
+
+func TestResolvePresetHardcore(t *testing.T) {
+	c, err := ResolvePreset(PresetHardcore, RuleOverrides{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.MobAggression != 1.75 || c.PickupHealScale != 0.5 || c.RespawnWaveInterval != 10 || c.RamKickCount != 5 ||
+		c.MaxPickups != 2 || c.PickupSpawnInterval != 40 {
+		t.Errorf("unexpected hardcore rules: %+v", c)
+	}
+	if c.Boost != (BoostTuning{SpeedMul: PlayerBoostMul, AccelMul: PlayerBoostMul, Stamina: 3}) {
+		t.Errorf("hardcore boost should be a 3s tank, got %+v", c.Boost)
+	}
+	// Everything the preset doesn't mention stays at the defaults
+	def := DefaultMatchConfig()
+	if c.ScoreboardInterval != def.ScoreboardInterval || c.CatchUpSteps != def.CatchUpSteps || c.ProjectileCancel {
+		t.Errorf("hardcore should keep unrelated defaults, got %+v", c)
+	}
+}
+
+func TestResolvePresetOverrides(t *testing.T) {
+	aggr, stamina, cancel := 100.0, 8.0, true
+	c, err := ResolvePreset(PresetHardcore, RuleOverrides{
+		MobAggression:    &aggr,
+		BoostStamina:     &stamina,
+		ProjectileCancel: &cancel,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.MobAggression != MaxMobAggression {
+		t.Errorf("override should be clamped, got aggression %v", c.MobAggression)
+	}
+	if c.Boost.Stamina != 8 || !c.ProjectileCancel || c.PickupHealScale != 0.5 {
+		t.Errorf("overrides should layer on the preset, got %+v", c)
+	}
+
+	if custom, _ := ResolvePreset(PresetCustom, RuleOverrides{}); custom != DefaultMatchConfig().Sanitize() {
+		t.Error("custom without overrides should be the classic rules")
+	}
+	if _, err := ResolvePreset("nightmare", RuleOverrides{}); err != ErrUnknownPreset {
+		t.Errorf("expected ErrUnknownPreset, got %v", err)
+	}
+}
+
+func TestGameSessionRandomnessSeeded(t *testing.T) {
+	spawns := func(seed int64) [][2]float64 {
+		g := NewGameWithConfig(MatchConfig{Seed: seed})
+		p := g.AddPlayer("Seeded")
+		var out [][2]float64
+		for i := 0; i < 3; i++ {
+			p.Respawn()
+			out = append(out, [2]float64{p.X, p.Y})
+		}
+		return out
+	}
+	a, b := spawns(7), spawns(7)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("same seed should give the same respawn points, got %v vs %v", a, b)
+		}
+	}
+	if c := spawns(8); c[0] == a[0] {
+		t.Error("different seeds should give different respawn points")
+	}
+
+	// A host-set seed must reproduce them too, whatever the game started with
+	hostSpawns := func() [][2]float64 {
+		g := NewGame()
+		p := g.AddPlayer("Host")
+		if err := g.SetSeed(p.ID, 7); err != nil {
+			t.Fatal(err)
+		}
+		var out [][2]float64
+		for i := 0; i < 3; i++ {
+			p.Respawn()
+			out = append(out, [2]float64{p.X, p.Y})
+		}
+		return out
+	}
+	a, b = hostSpawns(), hostSpawns()
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("same host-set seed should give the same respawn points, got %v vs %v", a, b)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+const (
+	DefaultRamKickWindow = 60.0 // seconds
+	MinRamKickWindow     = 5.0
+	MaxRamKickWindow     = 600.0
+	MaxRamKickCount      = 50
+)
+
+// rammer picks which of two colliding ships drove into the other: the one
+// closing faster along the line between them
+func rammer(a, b *Player) *Player {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	if a.VX*dx+a.VY*dy >= -(b.VX*dx + b.VY*dy) {
+		return a
+	}
+	return b
+}
+
+// noteRam records a ram by p and returns true once they have rammed
+// RamKickCount ships within RamKickWindow, so the caller kicks them. Rams
+// are a legitimate, if suicidal, play; only a sustained pattern is treated
+// as griefing.
+func (g *Game) noteRam(p *Player) bool {
+	limit := g.config.RamKickCount
+	if limit <= 0 {
+		return false
+	}
+	now := g.time()
+	recent := p.rams[:0]
+	for _, t := range p.rams {
+		if now-t < g.config.RamKickWindow {
+			recent = append(recent, t)
+		}
+	}
+	p.rams = append(recent, now)
+	return len(p.rams) >= limit
+}
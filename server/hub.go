@@ -1,12 +1,22 @@
 package main
 
-import "sync"
+import (
+	"sync"
+	"time"
+)
 
 const (
 	maxConnsPerIP = 5
 	maxTotalConns = 1000
 )
 
+// Session creation rate limit defaults: at most CreateLimitPerIP creates per
+// IP in any CreateLimitWindow, so one client can't occupy every session slot
+const (
+	CreateLimitPerIP  = 3
+	CreateLimitWindow = time.Minute
+)
+
 // Hub manages all connected clients and routes them to sessions
 type Hub struct {
 	mu         sync.RWMutex
@@ -14,10 +24,16 @@ type Hub struct {
 	register   chan *Client
 	unregister chan *Client
 	sessions   *SessionManager
+	entities   *EntityBudget
 	// Connection limiting (mutex-protected, accessed from HTTP handlers)
 	connMu     sync.Mutex
 	ipConns    map[string]int
 	totalConns int
+	ipCreates  map[string][]time.Time // recent session creates per IP, oldest first
+	// Session creates allowed per IP in each createWindow (0 = unlimited,
+	// e.g. for a server under a load test)
+	createLimit  int
+	createWindow time.Duration
 }
 
 // NewHub creates a new Hub
@@ -28,7 +44,13 @@ func NewHub() *Hub {
 		unregister: make(chan *Client, 64),
 		sessions:   NewSessionManager(),
 		ipConns:    make(map[string]int),
+		ipCreates:  make(map[string][]time.Time),
+		entities:   NewEntityBudget(maxGlobalEntities),
+
+		createLimit:  CreateLimitPerIP,
+		createWindow: CreateLimitWindow,
 	}
+	h.sessions.budget = h.entities
 	return h
 }
 
@@ -61,6 +83,52 @@ func (h *Hub) TrackDisconnect(ip string) {
 	h.totalConns--
 }
 
+// AllowCreate reserves a session create from ip if it is within the rate
+// limit. Otherwise it returns false and how long until the next create is
+// allowed. A create that then fails must hand its slot back with CancelCreate.
+func (h *Hub) AllowCreate(ip string) (bool, time.Duration) {
+	if h.createLimit <= 0 {
+		return true, 0
+	}
+	h.connMu.Lock()
+	defer h.connMu.Unlock()
+	now := time.Now()
+	cutoff := now.Add(-h.createWindow)
+
+	recent := h.ipCreates[ip]
+	for len(recent) > 0 && !recent[0].After(cutoff) {
+		recent = recent[1:]
+	}
+	if len(recent) >= h.createLimit {
+		h.ipCreates[ip] = recent
+		return false, recent[0].Sub(cutoff)
+	}
+	h.ipCreates[ip] = append(recent, now)
+
+	// Drop IPs whose creates have all expired so the map doesn't grow unbounded
+	if len(h.ipCreates) > maxTotalConns {
+		for k, times := range h.ipCreates {
+			if len(times) == 0 || !times[len(times)-1].After(cutoff) {
+				delete(h.ipCreates, k)
+			}
+		}
+	}
+	return true, 0
+}
+
+// CancelCreate releases the newest create reserved by ip, so only sessions
+// that were actually created count toward the limit
+func (h *Hub) CancelCreate(ip string) {
+	if h.createLimit <= 0 {
+		return
+	}
+	h.connMu.Lock()
+	defer h.connMu.Unlock()
+	if recent := h.ipCreates[ip]; len(recent) > 0 {
+		h.ipCreates[ip] = recent[:len(recent)-1]
+	}
+}
+
 // Run processes register/unregister events
 func (h *Hub) Run() {
 	for {
@@ -84,8 +152,14 @@ func (h *Hub) Run() {
 					if sess != nil {
 						sess.Game.RemoveController(client.playerID)
 					}
+				} else if client.isSpectator {
+					sess := h.sessions.GetSession(client.sessionID)
+					if sess != nil {
+						sess.Game.RemoveSpectator(client.playerID)
+					}
 				} else {
-					h.sessions.RemovePlayer(client.sessionID, client.playerID)
+					// Dropped, not left: hold the ship for a rejoin
+					h.sessions.DisconnectPlayer(client.sessionID, client.playerID)
 				}
 			}
 		}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -19,14 +20,18 @@ import (
 
 var uuidRegex = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
 
+// testRejoinGrace and testIdleTimeout are the test servers' rejoin grace
+// and empty-session timeout, short enough to wait out
+const (
+	testRejoinGrace = 100 * time.Millisecond
+	testIdleTimeout = 150 * time.Millisecond
+)
+
 // startTestServer spins up an httptest.Server with a Hub and returns
 // the server, its WebSocket URL, and a cleanup func.
 func startTestServer(t *testing.T) (*httptest.Server, string, func()) {
 	t.Helper()
 
-	prevIdleTimeout := SessionIdleTimeout
-	SessionIdleTimeout = 150 * time.Millisecond
-
 	// Create a temp client dir with a minimal index.html
 	tmpDir := t.TempDir()
 	jsDir := filepath.Join(tmpDir, "js")
@@ -35,6 +40,8 @@ func startTestServer(t *testing.T) (*httptest.Server, string, func()) {
 	os.WriteFile(filepath.Join(jsDir, "main.js"), []byte("// test"), 0o644)
 
 	hub := NewHub()
+	hub.sessions.rejoinGrace = testRejoinGrace
+	hub.sessions.idleTimeout = testIdleTimeout
 	go hub.Run()
 
 	mux := SetupRoutes(hub, tmpDir)
@@ -43,7 +50,6 @@ func startTestServer(t *testing.T) (*httptest.Server, string, func()) {
 	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
 
 	return srv, wsURL, func() {
-		SessionIdleTimeout = prevIdleTimeout
 		srv.Close()
 	}
 }
@@ -366,7 +372,7 @@ func TestCreateAndLeaveSession(t *testing.T) {
 	sendMsg(t, c, "leave", nil)
 
 	// Give a moment for cleanup
-	time.Sleep(SessionIdleTimeout + 50*time.Millisecond)
+	time.Sleep(testIdleTimeout + 50*time.Millisecond)
 
 	// Session should be empty and cleaned up
 	sendMsg(t, c2, "check", map[string]string{"sid": sid})
@@ -586,6 +592,7 @@ func TestWSEndpoint(t *testing.T) {
 
 func TestHubClientCount(t *testing.T) {
 	hub := NewHub()
+	hub.sessions.rejoinGrace = testRejoinGrace
 	go hub.Run()
 
 	if hub.ClientCount() != 0 {
@@ -595,6 +602,142 @@ func TestHubClientCount(t *testing.T) {
 
 // ---------- Session manager tests ----------
 
+func TestHubCreateRateLimit(t *testing.T) {
+	hub := NewHub()
+	for i := 0; i < CreateLimitPerIP; i++ {
+		if ok, _ := hub.AllowCreate("1.2.3.4"); !ok {
+			t.Fatalf("create %d should be allowed", i+1)
+		}
+	}
+	ok, wait := hub.AllowCreate("1.2.3.4")
+	if ok {
+		t.Fatal("create over the limit should be throttled")
+	}
+	if wait <= 0 || wait > CreateLimitWindow {
+		t.Errorf("retry wait %v should be within the window", wait)
+	}
+	if ok, _ := hub.AllowCreate("5.6.7.8"); !ok {
+		t.Error("other IPs should not be affected")
+	}
+}
+
+func TestHubCreateRateLimitWindowExpires(t *testing.T) {
+	hub := NewHub()
+	hub.createWindow = 30 * time.Millisecond
+	for i := 0; i < CreateLimitPerIP; i++ {
+		hub.AllowCreate("1.2.3.4")
+	}
+	if ok, _ := hub.AllowCreate("1.2.3.4"); ok {
+		t.Fatal("expected throttle inside the window")
+	}
+	time.Sleep(40 * time.Millisecond)
+	if ok, _ := hub.AllowCreate("1.2.3.4"); !ok {
+		t.Error("creates should be allowed again after the window")
+	}
+}
+
+func TestHubCancelledCreateFreesSlot(t *testing.T) {
+	hub := NewHub()
+	for i := 0; i < CreateLimitPerIP; i++ {
+		hub.AllowCreate("1.2.3.4")
+	}
+	hub.CancelCreate("1.2.3.4")
+	if ok, _ := hub.AllowCreate("1.2.3.4"); !ok {
+		t.Error("a create that failed should not count toward the limit")
+	}
+	if ok, _ := hub.AllowCreate("1.2.3.4"); ok {
+		t.Error("the limit should still apply to successful creates")
+	}
+
+	hub.createLimit = 0
+	for i := 0; i < 2*CreateLimitPerIP; i++ {
+		if ok, _ := hub.AllowCreate("5.6.7.8"); !ok {
+			t.Fatalf("create %d should be allowed with the limit disabled", i+1)
+		}
+	}
+}
+
+func TestFailedCreateNotThrottled(t *testing.T) {
+	hub := NewHub()
+	for i := 0; i < maxSessions; i++ {
+		hub.sessions.sessions["placeholder-"+strconv.Itoa(i)] = &Session{}
+	}
+	c := NewClient(hub, nil, "1.2.3.4")
+	create, _ := json.Marshal(CreateMsg{Name: "Host"})
+	for i := 0; i < CreateLimitPerIP+1; i++ {
+		c.handleCreate(create)
+		var env Envelope
+		json.Unmarshal(<-c.send, &env)
+		if env.T != MsgError {
+			t.Fatalf("create %d: expected an error with every slot taken, got %s", i+1, env.T)
+		}
+	}
+	if ok, _ := hub.AllowCreate("1.2.3.4"); !ok {
+		t.Error("creates that failed should not count toward the rate limit")
+	}
+}
+
+func TestKickedPlayerCanRejoin(t *testing.T) {
+	hub := NewHub()
+	sess := hub.sessions.CreateSession("Arena")
+	c := NewClient(hub, nil, "1.2.3.4")
+	join, _ := json.Marshal(JoinMsg{Name: "Idler", SessionID: sess.ID})
+	welcome := func() *WelcomeMsg {
+		for {
+			select {
+			case raw := <-c.send:
+				var env struct {
+					T string     `json:"t"`
+					D WelcomeMsg `json:"d"`
+				}
+				json.Unmarshal(raw, &env)
+				if env.T == MsgWelcome {
+					return &env.D
+				}
+			default:
+				return nil
+			}
+		}
+	}
+
+	c.handleJoin(join)
+	first := welcome()
+	if first == nil {
+		t.Fatal("first join should be welcomed")
+	}
+	sess.Game.mu.Lock()
+	sess.Game.kick(sess.Game.players[first.ID], "idle")
+	sess.Game.mu.Unlock()
+
+	c.handleJoin(join)
+	second := welcome()
+	if second == nil || !sess.Game.HasPlayer(second.ID) {
+		t.Fatal("kicked player re-joining the same session should get a new ship")
+	}
+}
+
+func TestRapidCreateThrottled(t *testing.T) {
+	_, wsURL, cleanup := startTestServer(t)
+	defer cleanup()
+
+	conn := dialWS(t, wsURL)
+	defer conn.Close()
+	for i := 0; i < CreateLimitPerIP; i++ {
+		sendMsg(t, conn, "create", map[string]string{"name": "Spam", "sname": "Spam"})
+		if env := readEnvelope(t, conn); env.T != MsgCreated {
+			t.Fatalf("create %d: expected created, got %s", i+1, env.T)
+		}
+	}
+	sendMsg(t, conn, "create", map[string]string{"name": "Spam", "sname": "Spam"})
+	env := readEnvelope(t, conn)
+	if env.T != MsgError {
+		t.Fatalf("expected error after %d creates, got %s", CreateLimitPerIP, env.T)
+	}
+	if msg, _ := dataMap(t, env)["msg"].(string); !strings.Contains(msg, "too fast") {
+		t.Errorf("expected a rate-limit error, got %q", msg)
+	}
+}
+
 func TestSessionManagerCreateAndGet(t *testing.T) {
 	sm := NewSessionManager()
 	sess := sm.CreateSession("Battle")
@@ -628,26 +771,111 @@ func TestSessionManagerListSessions(t *testing.T) {
 }
 
 func TestSessionManagerRemovePlayer(t *testing.T) {
-	prevIdleTimeout := SessionIdleTimeout
-	SessionIdleTimeout = 20 * time.Millisecond
-	defer func() {
-		SessionIdleTimeout = prevIdleTimeout
-	}()
-
 	sm := NewSessionManager()
+	sm.idleTimeout = 20 * time.Millisecond
 	sess := sm.CreateSession("TempArena")
 	player := sess.Game.AddPlayer("TestPlayer")
 
 	sm.RemovePlayer(sess.ID, player.ID)
 
 	// Session should be cleaned up (0 players)
-	time.Sleep(SessionIdleTimeout + 20*time.Millisecond)
+	time.Sleep(sm.idleTimeout + 20*time.Millisecond)
 	got := sm.GetSession(sess.ID)
 	if got != nil {
 		t.Error("expected session to be removed after last player leaves")
 	}
 }
 
+func TestSessionManagerCloseWhenEmpty(t *testing.T) {
+	sm := NewSessionManager()
+	sess := sm.CreateSessionWith("Private", SessionOptions{CloseWhenEmpty: true})
+	host := sess.Game.AddPlayer("Host")
+	guest := sess.Game.AddPlayer("Guest")
+	other := sess.Game.AddPlayer("Other")
+
+	sm.RemovePlayer(sess.ID, guest.ID)
+	if sm.GetSession(sess.ID) == nil {
+		t.Fatal("session should stay open while players remain")
+	}
+
+	sm.RemovePlayer(sess.ID, other.ID)
+	if sm.GetSession(sess.ID) == nil {
+		t.Fatal("session should stay open while the host remains")
+	}
+	sm.RemovePlayer(sess.ID, host.ID)
+	if sm.GetSession(sess.ID) != nil {
+		t.Error("expected session to be removed as soon as the last player leaves")
+	}
+}
+
+func TestSessionManagerCloseOnHostLeave(t *testing.T) {
+	sm := NewSessionManager()
+	sess := sm.CreateSessionWith("Private", SessionOptions{CloseWhenEmpty: true})
+	host := sess.Game.AddPlayer("Host")
+	guest := sess.Game.AddPlayer("Guest")
+	sess.Game.AddBot(host.ID)
+	mock := &mockBroadcaster{}
+	sess.Game.SetClient(guest.ID, mock)
+
+	sm.RemovePlayer(sess.ID, host.ID)
+	if sm.GetSession(sess.ID) != nil {
+		t.Fatal("private session should close when its host leaves, even with a guest and a bot left")
+	}
+	if countMsgType(mock, MsgKicked) != 1 {
+		t.Error("guest should be told the session closed")
+	}
+
+	open := sm.CreateSession("Public")
+	h := open.Game.AddPlayer("Host")
+	open.Game.AddPlayer("Guest")
+	sm.RemovePlayer(open.ID, h.ID)
+	if sm.GetSession(open.ID) == nil {
+		t.Error("a normal session should outlive its host")
+	}
+}
+
+func TestSessionManagerPreset(t *testing.T) {
+	sm := NewSessionManager()
+	sess := sm.CreateSessionWith("Chaos", SessionOptions{Preset: PresetChaos, Seed: 42})
+	if sess == nil {
+		t.Fatal("expected a session")
+	}
+	cfg := sess.Game.config
+	if !cfg.ProjectileCancel || cfg.MobWeights != (MobWeights{Tie: 1, StarDestroyer: 1}) || cfg.Seed != 42 {
+		t.Errorf("session should run the chaos rules with its seed, got %+v", cfg)
+	}
+	if sm.CreateSessionWith("Bad", SessionOptions{Preset: "nightmare"}) != nil {
+		t.Error("unknown preset should not create a session")
+	}
+}
+
+func TestCreateEphemeralSessionOverWS(t *testing.T) {
+	_, wsURL, cleanup := startTestServer(t)
+	defer cleanup()
+
+	conn := dialWS(t, wsURL)
+	defer conn.Close()
+	sendMsg(t, conn, "create", map[string]interface{}{"name": "Host", "sname": "Private", "ephemeral": true})
+	sid := dataMap(t, readEnvelope(t, conn))["sid"].(string)
+	sendMsg(t, conn, "join", map[string]string{"name": "Host", "sid": sid})
+	readEnvelope(t, conn) // joined
+	readEnvelope(t, conn) // welcome
+
+	sendMsg(t, conn, "leave", nil)
+	sendMsg(t, conn, "check", map[string]string{"sid": sid})
+	for i := 0; i < 20; i++ {
+		env := readEnvelope(t, conn)
+		if env.T != MsgChecked {
+			continue
+		}
+		if exists, _ := dataMap(t, env)["exists"].(bool); exists {
+			t.Error("ephemeral session should be gone right after its last player left")
+		}
+		return
+	}
+	t.Fatal("no check response")
+}
+
 // ---------- Util functions ----------
 
 func TestGenerateIDLength(t *testing.T) {
@@ -755,6 +983,264 @@ func TestLeaveWithoutJoining(t *testing.T) {
 
 // ---------- Create session, disconnect, session cleaned up ----------
 
+func TestJoinSecondSessionLeavesFirst(t *testing.T) {
+	srv, wsURL, cleanup := startTestServer(t)
+	_ = srv
+	defer cleanup()
+
+	c1 := dialWS(t, wsURL)
+	defer c1.Close()
+	first := createAndJoin(t, c1, "Hopper", "First")
+	second := createAndJoin(t, c1, "Hopper", "Second")
+
+	c2 := dialWS(t, wsURL)
+	defer c2.Close()
+	sendMsg(t, c2, "check", map[string]string{"sid": first})
+	checked := dataMap(t, readEnvelope(t, c2))
+	if checked["players"] != nil {
+		t.Errorf("first session should have no players left, got %v", checked["players"])
+	}
+	sendMsg(t, c2, "check", map[string]string{"sid": second})
+	checked = dataMap(t, readEnvelope(t, c2))
+	if checked["players"] != float64(1) {
+		t.Errorf("second session should have 1 player, got %v", checked["players"])
+	}
+}
+
+func TestJoinFullSessionKeepsShip(t *testing.T) {
+	srv, wsURL, cleanup := startTestServer(t)
+	_ = srv
+	defer cleanup()
+
+	host := dialWS(t, wsURL)
+	defer host.Close()
+	full := createAndJoin(t, host, "Host", "Full")
+	for i := 1; i < maxPlayersPerSession; i++ {
+		sendMsg(t, host, "add_bot", nil)
+	}
+	// Messages on one connection are handled in order, so this check
+	// answers once every bot is in
+	sendMsg(t, host, "check", map[string]string{"sid": full})
+	for {
+		env := readEnvelope(t, host)
+		if env.T != MsgChecked {
+			continue
+		}
+		if n := dataMap(t, env)["players"]; n != float64(maxPlayersPerSession) {
+			t.Fatalf("expected a full session, got %v players", n)
+		}
+		break
+	}
+
+	c := dialWS(t, wsURL)
+	defer c.Close()
+	home := createAndJoin(t, c, "Homebody", "Home")
+	sendMsg(t, c, "join", map[string]string{"name": "Homebody", "sid": full})
+	for {
+		env := readEnvelope(t, c)
+		if env.T == MsgError {
+			break
+		}
+		if env.T == MsgJoined {
+			t.Fatal("joining a full session should fail")
+		}
+	}
+	sendMsg(t, c, "check", map[string]string{"sid": home})
+	for {
+		env := readEnvelope(t, c)
+		if env.T != MsgChecked {
+			continue
+		}
+		if dataMap(t, env)["players"] != float64(1) {
+			t.Errorf("a failed join should keep the old ship, got %v players", dataMap(t, env)["players"])
+		}
+		return
+	}
+}
+
+func TestJoinSameEphemeralSessionTwice(t *testing.T) {
+	srv, wsURL, cleanup := startTestServer(t)
+	_ = srv
+	defer cleanup()
+
+	c := dialWS(t, wsURL)
+	defer c.Close()
+	sendMsg(t, c, "create", map[string]interface{}{"name": "Host", "sname": "Private", "ephemeral": true})
+	sid := dataMap(t, readEnvelope(t, c))["sid"].(string)
+	for i := 0; i < 2; i++ {
+		sendMsg(t, c, "join", map[string]string{"name": "Host", "sid": sid})
+	}
+	if env := readEnvelope(t, c); env.T != MsgJoined {
+		t.Fatalf("expected joined, got %s", env.T)
+	}
+	sendMsg(t, c, "check", map[string]string{"sid": sid})
+	for {
+		env := readEnvelope(t, c)
+		if env.T != MsgChecked {
+			continue
+		}
+		d := dataMap(t, env)
+		if d["exists"] != true || d["players"] != float64(1) {
+			t.Errorf("joining the current session again should keep it open with 1 player, got %v", d)
+		}
+		return
+	}
+}
+
+func TestChatRelayedToSession(t *testing.T) {
+	srv, wsURL, cleanup := startTestServer(t)
+	_ = srv
+	defer cleanup()
+
+	c1 := dialWS(t, wsURL)
+	defer c1.Close()
+	sid := createAndJoin(t, c1, "Talker", "ChatRoom")
+
+	c2 := dialWS(t, wsURL)
+	defer c2.Close()
+	sendMsg(t, c2, "join", map[string]string{"name": "Listener", "sid": sid})
+	_ = readEnvelope(t, c2) // joined
+	_ = readEnvelope(t, c2) // welcome
+
+	sendMsg(t, c1, "chat", map[string]string{"text": "   "}) // dropped
+	sendMsg(t, c1, "chat", map[string]string{"text": "  " + strings.Repeat("x", maxChatLen+50) + " "})
+
+	for {
+		env := readEnvelope(t, c2)
+		if env.T != MsgChatMsg {
+			continue
+		}
+		d := dataMap(t, env)
+		if d["name"] != "Talker" {
+			t.Errorf("expected sender name Talker, got %v", d["name"])
+		}
+		if text := d["text"].(string); text != strings.Repeat("x", maxChatLen) {
+			t.Errorf("expected text trimmed to %d chars, got %d", maxChatLen, len(text))
+		}
+		return
+	}
+}
+
+func TestSpectateSession(t *testing.T) {
+	srv, wsURL, cleanup := startTestServer(t)
+	_ = srv
+	defer cleanup()
+
+	c1 := dialWS(t, wsURL)
+	defer c1.Close()
+	sid := createAndJoin(t, c1, "Player", "Arena")
+
+	viewer := dialWS(t, wsURL)
+	sendMsg(t, viewer, "spectate", map[string]string{"sid": sid})
+	if env := readEnvelope(t, viewer); env.T != MsgSpectating {
+		t.Fatalf("expected spectating, got %s", env.T)
+	}
+	if env := readEnvelope(t, viewer); env.T != MsgState {
+		t.Fatalf("spectator should receive state frames, got %s", env.T)
+	}
+
+	c2 := dialWS(t, wsURL)
+	defer c2.Close()
+	listSpectators := func() interface{} {
+		sendMsg(t, c2, "list", nil)
+		env := readEnvelope(t, c2)
+		raw, _ := json.Marshal(env.Data)
+		var list []SessionInfo
+		json.Unmarshal(raw, &list)
+		for _, s := range list {
+			if s.ID == sid {
+				if s.Players != 1 {
+					t.Errorf("spectator should not count as a player, got %d players", s.Players)
+				}
+				return s.Spectators
+			}
+		}
+		t.Fatal("session missing from list")
+		return nil
+	}
+	if n := listSpectators(); n != 1 {
+		t.Errorf("expected 1 spectator, got %v", n)
+	}
+
+	viewer.Close()
+	time.Sleep(50 * time.Millisecond)
+	if n := listSpectators(); n != 0 {
+		t.Errorf("spectator should be removed on disconnect, got %v", n)
+	}
+}
+
+// joinWithToken creates and joins a session, returning its ID and the rejoin token
+func joinWithToken(t *testing.T, conn *websocket.Conn, name string) (string, string) {
+	t.Helper()
+	sendMsg(t, conn, "create", map[string]string{"name": name, "sname": "Rejoin"})
+	sid := dataMap(t, readEnvelope(t, conn))["sid"].(string)
+	sendMsg(t, conn, "join", map[string]string{"name": name, "sid": sid})
+	_ = readEnvelope(t, conn) // joined
+	welcome := readEnvelope(t, conn)
+	if welcome.T != MsgWelcome {
+		t.Fatalf("expected welcome, got %s", welcome.T)
+	}
+	return sid, dataMap(t, welcome)["token"].(string)
+}
+
+func TestRejoinAfterDrop(t *testing.T) {
+	srv, wsURL, cleanup := startTestServer(t)
+	_ = srv
+	defer cleanup()
+
+	c1 := dialWS(t, wsURL)
+	sid, token := joinWithToken(t, c1, "Flaky")
+	c1.Close()
+	time.Sleep(20 * time.Millisecond)
+
+	c2 := dialWS(t, wsURL)
+	defer c2.Close()
+	sendMsg(t, c2, "rejoin", map[string]string{"sid": sid, "token": "wrong"})
+	if env := readEnvelope(t, c2); env.T != MsgError {
+		t.Fatalf("bad token should be rejected, got %s", env.T)
+	}
+	sendMsg(t, c2, "rejoin", map[string]string{"sid": sid, "token": token})
+	if env := readEnvelope(t, c2); env.T != MsgJoined {
+		t.Fatalf("expected joined, got %s", env.T)
+	}
+	if env := readEnvelope(t, c2); env.T != MsgWelcome || dataMap(t, env)["token"] != token {
+		t.Fatalf("expected welcome for the same ship, got %s %v", env.T, env.Data)
+	}
+
+	// Past the grace window the ship is still ours
+	time.Sleep(testRejoinGrace + 50*time.Millisecond)
+	sendMsg(t, c2, "check", map[string]string{"sid": sid})
+	for {
+		env := readEnvelope(t, c2)
+		if env.T != MsgChecked {
+			continue
+		}
+		if dataMap(t, env)["players"] != float64(1) {
+			t.Errorf("rejoined player should stay in the session, got %v", dataMap(t, env)["players"])
+		}
+		break
+	}
+}
+
+func TestRejoinExpires(t *testing.T) {
+	srv, wsURL, cleanup := startTestServer(t)
+	_ = srv
+	defer cleanup()
+
+	c1 := dialWS(t, wsURL)
+	sid, token := joinWithToken(t, c1, "Gone")
+	c1.Close()
+	time.Sleep(testRejoinGrace + 50*time.Millisecond)
+
+	c2 := dialWS(t, wsURL)
+	defer c2.Close()
+	sendMsg(t, c2, "rejoin", map[string]string{"sid": sid, "token": token})
+	env := readEnvelope(t, c2)
+	if env.T != MsgError {
+		t.Fatalf("rejoin after the grace window should fail, got %s", env.T)
+	}
+}
+
 func TestDisconnectCleansUpSession(t *testing.T) {
 	srv, wsURL, cleanup := startTestServer(t)
 	_ = srv
@@ -766,8 +1252,8 @@ func TestDisconnectCleansUpSession(t *testing.T) {
 	// Disconnect
 	c1.Close()
 
-	// Wait for hub to process unregister
-	time.Sleep(SessionIdleTimeout + 50*time.Millisecond)
+	// Wait out the rejoin grace, then the idle timeout
+	time.Sleep(testRejoinGrace + testIdleTimeout + 50*time.Millisecond)
 
 	// Check if session is gone
 	c2 := dialWS(t, wsURL)
@@ -778,3 +1264,81 @@ func TestDisconnectCleansUpSession(t *testing.T) {
 		t.Error("session should be cleaned up after disconnect")
 	}
 }
+
+func TestDebugFeedGatedByServerFlag(t *testing.T) {
+	_, wsURL, cleanup := startTestServer(t)
+	defer cleanup()
+	prev := DebugFeedEnabled
+	defer func() { DebugFeedEnabled = prev }()
+
+	conn := dialWS(t, wsURL)
+	defer conn.Close()
+	createAndJoin(t, conn, "Dev", "Debug")
+
+	waitFor := func(want string) Envelope {
+		t.Helper()
+		for i := 0; i < 50; i++ {
+			env := readEnvelope(t, conn)
+			if env.T == want {
+				return env
+			}
+		}
+		t.Fatalf("no %s message received", want)
+		return Envelope{}
+	}
+
+	DebugFeedEnabled = false
+	sendMsg(t, conn, MsgDebug, DebugMsg{On: true})
+	if msg := dataMap(t, waitFor(MsgError))["msg"]; msg != "debug feed disabled" {
+		t.Fatalf("expected debug feed to be refused, got %v", msg)
+	}
+
+	DebugFeedEnabled = true
+	sendMsg(t, conn, MsgDebug, DebugMsg{On: true})
+	d := dataMap(t, waitFor(MsgDebugState))
+	for _, key := range []string{"cells", "checks", "targets"} {
+		if _, ok := d[key]; !ok {
+			t.Errorf("debug state missing %q", key)
+		}
+	}
+}
+
+func TestIdlePlayerKicked(t *testing.T) {
+	sm := NewSessionManager()
+	sess := sm.CreateSessionWith("AFKArena", SessionOptions{CloseWhenEmpty: true})
+	// Set under the game lock: the session's loop is already running
+	sess.Game.mu.Lock()
+	sess.Game.config.AFKWarnAfter, sess.Game.config.AFKKickAfter = 0.1, 0.2
+	sess.Game.mu.Unlock()
+	p := sess.Game.AddPlayer("Sleepy")
+	mock := &mockBroadcaster{}
+	sess.Game.SetClient(p.ID, mock)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for sess.Game.HasPlayer(p.ID) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if sess.Game.HasPlayer(p.ID) {
+		t.Fatal("idle player should have been kicked")
+	}
+	var sent []string
+	mock.mu.Lock()
+	for _, msg := range mock.messages {
+		if env, ok := msg.(Envelope); ok && (env.T == MsgError || env.T == MsgKicked) {
+			sent = append(sent, env.T)
+		}
+	}
+	mock.mu.Unlock()
+	if len(sent) != 2 || sent[0] != MsgError || sent[1] != MsgKicked {
+		t.Errorf("idle player should be warned, then told they were kicked, got %v", sent)
+	}
+
+	// The kick cleans up through the manager like a leave (see onKick)
+	deadline = time.Now().Add(time.Second)
+	for sm.GetSession(sess.ID) != nil && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if sm.GetSession(sess.ID) != nil {
+		t.Error("session emptied by an idle kick should be cleaned up")
+	}
+}
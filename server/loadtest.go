@@ -0,0 +1,255 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	LoadTestInputRate  = 20 // inputs per second per bot, matching the real client
+	LoadTestPerSession = 10 // bots per created session
+	loadTestReplyWait  = 5 * time.Second
+)
+
+// LoadTestConfig configures a load-test run against a running server
+type LoadTestConfig struct {
+	URL        string        // WebSocket URL, e.g. ws://host:8080/ws
+	Conns      int           // total bot connections
+	PerSession int           // bots sharing one session (0 = LoadTestPerSession)
+	Duration   time.Duration // how long each bot sends input
+}
+
+// LoadTestResult summarizes a load-test run
+type LoadTestResult struct {
+	Connected  int           // bots that joined a session
+	Failed     int           // bots that failed to connect, create or join
+	Frames     uint64        // state frames received across all bots
+	Samples    uint64        // input -> next state frame samples
+	AvgLatency time.Duration // mean time from sending input to the next state frame
+	MaxLatency time.Duration
+	Errors     []string // first error per failed bot
+}
+
+func (r LoadTestResult) String() string {
+	return fmt.Sprintf("connected=%d failed=%d frames=%d latency avg=%v max=%v (%d samples)",
+		r.Connected, r.Failed, r.Frames, r.AvgLatency, r.MaxLatency, r.Samples)
+}
+
+// loadStats is shared by every bot in a run
+type loadStats struct {
+	frames    atomic.Uint64
+	samples   atomic.Uint64
+	latencyNs atomic.Int64
+	maxNs     atomic.Int64
+}
+
+func (s *loadStats) observe(d time.Duration) {
+	s.samples.Add(1)
+	s.latencyNs.Add(int64(d))
+	for {
+		cur := s.maxNs.Load()
+		if int64(d) <= cur || s.maxNs.CompareAndSwap(cur, int64(d)) {
+			return
+		}
+	}
+}
+
+// RunLoadTest opens cfg.Conns bot connections, groups them into sessions,
+// and sends synthetic input for cfg.Duration
+func RunLoadTest(cfg LoadTestConfig) (LoadTestResult, error) {
+	if cfg.URL == "" {
+		return LoadTestResult{}, errors.New("loadtest: missing URL")
+	}
+	if cfg.Conns <= 0 {
+		return LoadTestResult{}, errors.New("loadtest: need at least one connection")
+	}
+	if cfg.PerSession <= 0 {
+		cfg.PerSession = LoadTestPerSession
+	}
+	cfg.PerSession = min(cfg.PerSession, maxPlayersPerSession)
+
+	var (
+		stats loadStats
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		res   LoadTestResult
+	)
+	report := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			res.Failed++
+			res.Errors = append(res.Errors, err.Error())
+		} else {
+			res.Connected++
+		}
+	}
+
+	for start := 0; start < cfg.Conns; start += cfg.PerSession {
+		n := min(cfg.PerSession, cfg.Conns-start)
+		wg.Add(1)
+		go func(first, n int) {
+			defer wg.Done()
+			// The first bot creates the session and then plays in it, so a
+			// run never holds more connections than bots
+			conn, sid, err := loadTestCreate(cfg.URL, first)
+			if err != nil {
+				for i := 0; i < n; i++ {
+					report(err)
+				}
+				return
+			}
+			var bots sync.WaitGroup
+			for i := 0; i < n; i++ {
+				bots.Add(1)
+				go func(conn *websocket.Conn, id int) {
+					defer bots.Done()
+					runLoadBot(cfg, conn, sid, id, &stats, report)
+				}(conn, first+i)
+				conn = nil
+			}
+			bots.Wait()
+		}(start, n)
+	}
+	wg.Wait()
+
+	res.Frames = stats.frames.Load()
+	res.Samples = stats.samples.Load()
+	res.MaxLatency = time.Duration(stats.maxNs.Load())
+	if res.Samples > 0 {
+		res.AvgLatency = time.Duration(stats.latencyNs.Load() / int64(res.Samples))
+	}
+	return res, nil
+}
+
+// loadTestCreate dials, creates a session and returns the open connection and session ID
+func loadTestCreate(url string, id int) (*websocket.Conn, string, error) {
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("bot %d: dial: %w", id, err)
+	}
+
+	create := Envelope{T: MsgCreate, Data: CreateMsg{Name: "LoadBot", SessionName: fmt.Sprintf("Load %d", id)}}
+	if err := conn.WriteJSON(create); err != nil {
+		conn.Close()
+		return nil, "", fmt.Errorf("bot %d: create: %w", id, err)
+	}
+	var created struct {
+		SID string `json:"sid"`
+	}
+	if err := loadTestAwait(conn, MsgCreated, &created); err != nil {
+		conn.Close()
+		return nil, "", fmt.Errorf("bot %d: create: %w", id, err)
+	}
+	return conn, created.SID, nil
+}
+
+// loadTestAwait reads until a text message of type want arrives, decoding its payload into v
+func loadTestAwait(conn *websocket.Conn, want string, v interface{}) error {
+	conn.SetReadDeadline(time.Now().Add(loadTestReplyWait))
+	defer conn.SetReadDeadline(time.Time{})
+	for {
+		mt, raw, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		if mt != websocket.TextMessage {
+			continue
+		}
+		var env InEnvelope
+		if err := json.Unmarshal(raw, &env); err != nil {
+			return err
+		}
+		switch env.T {
+		case want:
+			if v == nil {
+				return nil
+			}
+			return json.Unmarshal(env.D, v)
+		case MsgError:
+			var e ErrorMsg
+			json.Unmarshal(env.D, &e)
+			return errors.New(e.Msg)
+		}
+	}
+}
+
+// runLoadBot joins sid and steers in circles, firing, until cfg.Duration elapses.
+// It dials its own connection unless conn is non-nil.
+func runLoadBot(cfg LoadTestConfig, conn *websocket.Conn, sid string, id int, stats *loadStats, report func(error)) {
+	if conn == nil {
+		var err error
+		conn, _, err = websocket.DefaultDialer.Dial(cfg.URL, nil)
+		if err != nil {
+			report(fmt.Errorf("bot %d: dial: %w", id, err))
+			return
+		}
+	}
+	defer conn.Close()
+
+	join := Envelope{T: MsgJoin, Data: JoinMsg{Name: fmt.Sprintf("Bot%d", id), SessionID: sid}}
+	if err := conn.WriteJSON(join); err != nil {
+		report(fmt.Errorf("bot %d: join: %w", id, err))
+		return
+	}
+	if err := loadTestAwait(conn, MsgJoined, nil); err != nil {
+		report(fmt.Errorf("bot %d: join: %w", id, err))
+		return
+	}
+	report(nil)
+
+	// Reader: count state frames and time input -> next frame
+	var pending atomic.Int64 // unix nanos of the oldest unanswered input, 0 if none
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			mt, _, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if mt != websocket.BinaryMessage {
+				continue
+			}
+			stats.frames.Add(1)
+			if sent := pending.Swap(0); sent != 0 {
+				stats.observe(time.Duration(time.Now().UnixNano() - sent))
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(time.Second / LoadTestInputRate)
+	defer ticker.Stop()
+	deadline := time.After(cfg.Duration)
+	cx, cy := WorldWidth/2, WorldHeight/2
+	for step := 0; ; step++ {
+		select {
+		case <-ticker.C:
+			a := float64(step+id*7) * 0.1
+			in := Envelope{T: MsgInput, Data: ClientInput{
+				MX:     cx + math.Cos(a)*400,
+				MY:     cy + math.Sin(a)*400,
+				Fire:   step%40 < 25,
+				Thresh: 200,
+			}}
+			if err := conn.WriteJSON(in); err != nil {
+				return
+			}
+			pending.CompareAndSwap(0, time.Now().UnixNano())
+		case <-deadline:
+			conn.WriteJSON(Envelope{T: MsgLeave})
+			conn.Close()
+			<-done
+			return
+		case <-done:
+			return
+		}
+	}
+}
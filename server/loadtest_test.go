@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadTestDrivesConnections(t *testing.T) {
+	_, wsURL, cleanup := startTestServer(t)
+	defer cleanup()
+
+	res, err := RunLoadTest(LoadTestConfig{
+		URL:        wsURL,
+		Conns:      4,
+		PerSession: 2,
+		Duration:   300 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("load test: %v", err)
+	}
+	if res.Connected != 4 || res.Failed != 0 {
+		t.Fatalf("expected 4 connected / 0 failed, got %s (errors: %v)", res, res.Errors)
+	}
+	if res.Frames == 0 {
+		t.Error("bots should have received state frames")
+	}
+	if res.Samples == 0 || res.AvgLatency <= 0 || res.MaxLatency < res.AvgLatency {
+		t.Errorf("expected input->state latency samples, got %s", res)
+	}
+}
+
+func TestLoadTestBadURL(t *testing.T) {
+	res, err := RunLoadTest(LoadTestConfig{URL: "ws://127.0.0.1:1/ws", Conns: 2, Duration: time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected config error: %v", err)
+	}
+	if res.Connected != 0 || res.Failed != 2 || len(res.Errors) != 2 {
+		t.Errorf("expected both bots to fail, got %s", res)
+	}
+	if _, err := RunLoadTest(LoadTestConfig{URL: "ws://x", Conns: 0}); err == nil {
+		t.Error("zero connections should be rejected")
+	}
+}
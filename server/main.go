@@ -2,19 +2,52 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"syscall"
+	"time"
 )
 
 func main() {
 	addr := flag.String("addr", ":8080", "HTTP listen address")
 	clientRustDir := flag.String("client-rust", "", "Path to Rust client dist directory (default: ../client-rust/dist)")
+	phrasesPath := flag.String("phrases", "", "Path to a JSON mob phrase pack (default: built-in English phrases)")
+	createLimit := flag.Int("create-limit", CreateLimitPerIP, "Session creates allowed per IP per minute (0 = unlimited, e.g. for a server under -loadtest)")
+	debugFeed := flag.Bool("debug-feed", false, "Allow clients to request the developer debug state feed (never enable in production)")
+	loadURL := flag.String("loadtest", "", "Run as a load-test client against this WebSocket URL (e.g. ws://host:8080/ws) instead of serving")
+	loadConns := flag.Int("loadtest-conns", 10, "Load test: number of bot connections (the server allows 5 per IP, and 3 session creates per minute unless run with -create-limit 0)")
+	loadPer := flag.Int("loadtest-per-session", LoadTestPerSession, "Load test: bots per session")
+	loadDur := flag.Duration("loadtest-duration", 30*time.Second, "Load test: how long bots send input")
 	flag.Parse()
 
+	DebugFeedEnabled = *debugFeed
+
+	if *loadURL != "" {
+		res, err := RunLoadTest(LoadTestConfig{URL: *loadURL, Conns: *loadConns, PerSession: *loadPer, Duration: *loadDur})
+		if err != nil {
+			log.Fatalf("load test: %v", err)
+		}
+		for _, e := range res.Errors {
+			log.Printf("load test: %s", e)
+		}
+		fmt.Println(res)
+		if res.Connected == 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *phrasesPath != "" {
+		if err := LoadPhrasePack(*phrasesPath); err != nil {
+			log.Fatalf("load phrases: %v", err)
+		}
+		log.Printf("Loaded mob phrase pack from %s", *phrasesPath)
+	}
+
 	if *clientRustDir == "" {
 		exe, _ := os.Executable()
 		*clientRustDir = filepath.Join(filepath.Dir(exe), "..", "client-rust", "dist")
@@ -29,10 +62,26 @@ func main() {
 	}
 
 	hub := NewHub()
+	hub.createLimit = *createLimit
 	go hub.Run()
 
 	mux := SetupRoutes(hub, *clientRustDir)
 
+	// SIGHUP reloads the mob phrase pack without dropping connections.
+	// Balance settings come from each session's MatchConfig and aren't
+	// reloaded.
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			if err := ReloadPhrases(*phrasesPath); err != nil {
+				log.Printf("reload phrases: %v (keeping current phrases)", err)
+				continue
+			}
+			log.Printf("Reloaded phrases")
+		}
+	}()
+
 	// Graceful shutdown
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
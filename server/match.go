@@ -0,0 +1,330 @@
+package main
+
+import "math"
+
+const (
+	MinMobAggression = 0.25
+	MaxMobAggression = 4.0
+
+	DefaultMobChatterCooldown = 0.5 // seconds between mob phrases per session
+	MaxMobChatterCooldown     = 30.0
+
+	MinPickupHealScale = 0.25
+	MaxPickupHealScale = 4.0
+
+	MaxDeathBlastRadius = 300.0
+
+	MaxRespawnWaveInterval = 30.0
+
+	MinSpawnInterval = 1.0
+	MaxSpawnInterval = 120.0
+
+	MaxSeed = 1<<53 - 1 // seeds stay exact as JSON numbers
+)
+
+// MatchConfig holds per-session gameplay tuning
+type MatchConfig struct {
+	// MobAggression scales mob detect/shoot/optimal ranges and fire rate (1 = default)
+	MobAggression float64
+
+	// DisableMobChatter suppresses all MsgMobSay broadcasts
+	DisableMobChatter bool
+	// MobChatterCooldown is the session-wide minimum gap between mob phrases
+	// (seconds). 0 means DefaultMobChatterCooldown, so the limit can't be
+	// switched off outright; anything up to one tick (1/TickRate) lets a
+	// phrase through every tick.
+	MobChatterCooldown float64
+
+	// SpatialCellSize overrides the broad-phase grid cell size (0 = SpatialCellSize).
+	// Modes with larger entities can raise it to about their largest
+	// diameter, so most entities touch 1-4 cells.
+	SpatialCellSize float64
+
+	// Seed drives the session's PvE spawn patterns (0 = pick a random seed).
+	// Sessions with the same seed spawn the same mobs, asteroids and pickups.
+	Seed int64
+
+	// StaticAsteroids is how many permanent asteroids to place from the seed
+	// as cover (0 = none, up to MaxStaticAsteroids)
+	StaticAsteroids int
+
+	// PickupMagnetRadius lets hurt players (HP at or below PickupLowHP)
+	// pull health pickups within this range toward them (0 = contact only,
+	// up to MaxPickupMagnetRadius)
+	PickupMagnetRadius float64
+
+	// PickupHealScale multiplies PickupHeal (1 = default). Long attrition
+	// matches can raise it; fast, lethal ones can lower it.
+	PickupHealScale float64
+
+	// PickupShieldTime grants a hurt player (HP at or below PickupLowHP) who
+	// grabs a pickup this many seconds of damage reduction, so they aren't
+	// finished off on the spot (0 = off, up to MaxPickupShieldTime)
+	PickupShieldTime float64
+	// PickupShieldReduction is the fraction of damage the shield blocks
+	// (0 = DefaultPickupShieldReduction, 1 = invulnerable)
+	PickupShieldReduction float64
+
+	// OvershieldCap lets healing past MaxHP build a decaying overshield of up
+	// to this many points, absorbed before HP (0 = off, up to MaxOvershield)
+	OvershieldCap int
+
+	// DeathBlastRadius and DeathBlastDamage make destroyed ships explode,
+	// damaging other ships within the radius (0 = off). Blast kills are
+	// credited to whoever last hit the exploding ship.
+	DeathBlastRadius float64
+	DeathBlastDamage int
+
+	// Boost tunes boost speed, thrust and stamina (zero value = the classic
+	// unlimited PlayerBoostMul boost)
+	Boost BoostTuning
+
+	// RamKickCount kicks a player who rams this many ships within
+	// RamKickWindow seconds (0 = off; window 0 = DefaultRamKickWindow)
+	RamKickCount  int
+	RamKickWindow float64
+
+	// AFKWarnAfter and AFKKickAfter are the seconds a connected player may
+	// send no input before being warned, then removed so the slot frees up
+	// (0 = DefaultAFKWarnAfter / DefaultAFKKickAfter; the warning never
+	// comes after the kick)
+	AFKWarnAfter float64
+	AFKKickAfter float64
+
+	// TurnAssist gives every player the faster turn toward far-off aim
+	// points that phone controllers get (see TurnAssistAngle)
+	TurnAssist bool
+
+	// DisableVelocityDelta sends every ship's velocity in every frame
+	// instead of omitting ones that barely changed since the last broadcast,
+	// for debugging and high-fidelity rooms
+	DisableVelocityDelta bool
+	// PlayerVelDelta and MobVelDelta are how much (px/s, per axis) a ship's
+	// velocity must change before it is resent (0 = DefaultVelDelta, up to
+	// MaxVelDelta). Projectiles fly straight, so their velocity is sent once.
+	PlayerVelDelta float64
+	MobVelDelta    float64
+
+	// ProjectileCancel makes shots from different owners destroy each other
+	// on contact, with a MsgSpark at the meeting point
+	ProjectileCancel bool
+
+	// ScoreboardInterval is the seconds between MsgScoreboard updates
+	// (0 = DefaultScoreboardInterval)
+	ScoreboardInterval float64
+
+	// MinPlayersForMobs holds off mob spawns until this many players are in
+	// the session (0 = spawn for anyone), so a solo pilot isn't mobbed
+	MinPlayersForMobs int
+	// PlayersPerExtraMob is how many players raise the live mob cap by one
+	// at the default MaxMobs; a larger MaxMobs grows the cap proportionally
+	// faster (0 = default of 3)
+	PlayersPerExtraMob int
+
+	// MobWeights sets the mob archetype mix (zero value = default mix)
+	MobWeights MobWeights
+
+	// MobSpawnInterval, AsteroidSpawnInterval and PickupSpawnInterval are
+	// the seconds between spawns of each kind (0 = the package defaults,
+	// between MinSpawnInterval and MaxSpawnInterval). Mobs below the live
+	// cap still refill quickly.
+	MobSpawnInterval      float64
+	AsteroidSpawnInterval float64
+	PickupSpawnInterval   float64
+	// MaxMobs, MaxAsteroids and MaxPickups cap live entities of each kind
+	// (0 = default, up to mobCapLimit etc.). The mob cap still scales with
+	// the player count up to MaxMobs (see recomputeCaps).
+	MaxMobs      int
+	MaxAsteroids int
+	MaxPickups   int
+
+	// RespawnWaveInterval batches respawns: dead players whose RespawnTime has
+	// passed come back together every this many seconds (0 = respawn each
+	// player RespawnTime after their death)
+	RespawnWaveInterval float64
+
+	// SpectateOnDeath streams dead players a living player's view (their
+	// killer if still alive) until they respawn
+	SpectateOnDeath bool
+
+	// CatchUpSteps caps the ticks run back to back after the game loop
+	// stalls (0 = DefaultCatchUpSteps). Higher values keep the simulation
+	// closer to real time after long stalls at the cost of bursty CPU.
+	CatchUpSteps int
+
+	// Deterministic makes a session reproducible tick for tick, for replays
+	// and regression tests. Entities are updated and collided in ID order,
+	// and IDs come from a per-session counter instead of crypto/rand.
+	// Physics stays float64 rather than fixed-point: the game is built on
+	// float math, and a strict update order is enough for bit-identical
+	// runs on one platform.
+	//
+	// Trade-offs: sorting costs a little per tick, and IDs are guessable,
+	// so don't enable it for public sessions (controllers attach by player
+	// ID). Spawns, respawn points and mob AI draw from sources seeded from
+	// Seed, so a replay needs the same Seed. On architectures where Go fuses
+	// multiply-adds (arm64, ppc64, s390x), results can still differ from
+	// amd64 in the last bits.
+	Deterministic bool
+}
+
+// MobWeights are relative spawn weights per mob archetype. The zero value
+// means the default mix (SDSpawnChance Star Destroyers, the rest TIEs).
+type MobWeights struct {
+	Tie           float64 `json:"tie"`
+	StarDestroyer float64 `json:"sd"`
+}
+
+// Valid reports whether the weights can be used: none negative or
+// non-finite (all zero selects the default mix)
+func (w MobWeights) Valid() bool {
+	for _, v := range []float64{w.Tie, w.StarDestroyer} {
+		if v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// sdChance is the probability that a spawned mob is a Star Destroyer
+func (w MobWeights) sdChance() float64 {
+	total := w.Tie + w.StarDestroyer
+	if total <= 0 {
+		return SDSpawnChance
+	}
+	return w.StarDestroyer / total
+}
+
+// DefaultMatchConfig returns the config used when a session doesn't override anything
+func DefaultMatchConfig() MatchConfig {
+	return MatchConfig{
+		MobAggression:      1.0,
+		MobChatterCooldown: DefaultMobChatterCooldown,
+		SpatialCellSize:    SpatialCellSize,
+		CatchUpSteps:       DefaultCatchUpSteps,
+		PickupHealScale:    1.0,
+		ScoreboardInterval: DefaultScoreboardInterval,
+		PlayersPerExtraMob: playersPerExtraMob,
+		Boost:              BoostTuning{SpeedMul: PlayerBoostMul, AccelMul: PlayerBoostMul},
+		RamKickWindow:      DefaultRamKickWindow,
+		AFKWarnAfter:       DefaultAFKWarnAfter,
+		AFKKickAfter:       DefaultAFKKickAfter,
+		PlayerVelDelta:     DefaultVelDelta,
+		MobVelDelta:        DefaultVelDelta,
+
+		MobSpawnInterval:      MobSpawnInterval,
+		AsteroidSpawnInterval: AsteroidSpawnInterval,
+		PickupSpawnInterval:   PickupSpawnInterval,
+		MaxMobs:               maxMobsPerSession,
+		MaxAsteroids:          maxAsteroidsPerSession,
+		MaxPickups:            maxPickupsPerSession,
+	}
+}
+
+// ValidSeed reports whether seed can be chosen for a session
+func ValidSeed(seed int64) bool {
+	return seed > 0 && seed <= MaxSeed
+}
+
+// Sanitize clamps config values to safe ranges, filling in defaults for zero values
+func (c MatchConfig) Sanitize() MatchConfig {
+	if c.MobAggression <= 0 {
+		c.MobAggression = 1.0
+	}
+	c.MobAggression = Clamp(c.MobAggression, MinMobAggression, MaxMobAggression)
+	if c.MobChatterCooldown <= 0 {
+		c.MobChatterCooldown = DefaultMobChatterCooldown
+	}
+	c.MobChatterCooldown = Clamp(c.MobChatterCooldown, 0, MaxMobChatterCooldown)
+	if c.SpatialCellSize <= 0 {
+		c.SpatialCellSize = SpatialCellSize
+	}
+	c.SpatialCellSize = Clamp(c.SpatialCellSize, MinSpatialCellSize, MaxSpatialCellSize)
+	if !ValidSeed(c.Seed) {
+		c.Seed = 0
+	}
+	c.StaticAsteroids = min(max(c.StaticAsteroids, 0), MaxStaticAsteroids)
+	c.PickupMagnetRadius = Clamp(c.PickupMagnetRadius, 0, MaxPickupMagnetRadius)
+	if c.PickupHealScale <= 0 {
+		c.PickupHealScale = 1.0
+	}
+	c.PickupHealScale = Clamp(c.PickupHealScale, MinPickupHealScale, MaxPickupHealScale)
+	c.PickupShieldTime = Clamp(c.PickupShieldTime, 0, MaxPickupShieldTime)
+	if c.PickupShieldReduction <= 0 {
+		c.PickupShieldReduction = DefaultPickupShieldReduction
+	}
+	c.PickupShieldReduction = Clamp(c.PickupShieldReduction, 0, 1)
+	c.OvershieldCap = min(max(c.OvershieldCap, 0), MaxOvershield)
+	if c.ScoreboardInterval <= 0 {
+		c.ScoreboardInterval = DefaultScoreboardInterval
+	}
+	c.ScoreboardInterval = Clamp(c.ScoreboardInterval, MinScoreboardInterval, MaxScoreboardInterval)
+	if c.PlayerVelDelta <= 0 {
+		c.PlayerVelDelta = DefaultVelDelta
+	}
+	c.PlayerVelDelta = Clamp(c.PlayerVelDelta, 0, MaxVelDelta)
+	if c.MobVelDelta <= 0 {
+		c.MobVelDelta = DefaultVelDelta
+	}
+	c.MobVelDelta = Clamp(c.MobVelDelta, 0, MaxVelDelta)
+	if c.Boost.SpeedMul <= 0 {
+		c.Boost.SpeedMul = PlayerBoostMul
+	}
+	c.Boost.SpeedMul = Clamp(c.Boost.SpeedMul, 1, MaxBoostMul)
+	if c.Boost.AccelMul <= 0 {
+		c.Boost.AccelMul = c.Boost.SpeedMul
+	}
+	c.Boost.AccelMul = Clamp(c.Boost.AccelMul, 1, MaxBoostMul)
+	c.Boost.Stamina = Clamp(c.Boost.Stamina, 0, MaxBoostStamina)
+	c.RamKickCount = min(max(c.RamKickCount, 0), MaxRamKickCount)
+	if c.RamKickWindow <= 0 {
+		c.RamKickWindow = DefaultRamKickWindow
+	}
+	c.RamKickWindow = Clamp(c.RamKickWindow, MinRamKickWindow, MaxRamKickWindow)
+	if c.AFKKickAfter <= 0 {
+		c.AFKKickAfter = DefaultAFKKickAfter
+	}
+	c.AFKKickAfter = min(c.AFKKickAfter, MaxAFKKickAfter)
+	if c.AFKWarnAfter <= 0 {
+		c.AFKWarnAfter = DefaultAFKWarnAfter
+	}
+	c.AFKWarnAfter = min(c.AFKWarnAfter, c.AFKKickAfter)
+	c.MinPlayersForMobs = min(max(c.MinPlayersForMobs, 0), maxPlayersPerSession)
+	if c.PlayersPerExtraMob <= 0 {
+		c.PlayersPerExtraMob = playersPerExtraMob
+	}
+	c.PlayersPerExtraMob = min(c.PlayersPerExtraMob, maxPlayersPerSession)
+	if !c.MobWeights.Valid() {
+		c.MobWeights = MobWeights{}
+	}
+	c.MobSpawnInterval = spawnInterval(c.MobSpawnInterval, MobSpawnInterval)
+	c.AsteroidSpawnInterval = spawnInterval(c.AsteroidSpawnInterval, AsteroidSpawnInterval)
+	c.PickupSpawnInterval = spawnInterval(c.PickupSpawnInterval, PickupSpawnInterval)
+	c.MaxMobs = entityCap(c.MaxMobs, maxMobsPerSession, mobCapLimit)
+	c.MaxAsteroids = entityCap(c.MaxAsteroids, maxAsteroidsPerSession, asteroidCapLimit)
+	c.MaxPickups = entityCap(c.MaxPickups, maxPickupsPerSession, pickupCapLimit)
+	c.RespawnWaveInterval = Clamp(c.RespawnWaveInterval, 0, MaxRespawnWaveInterval)
+	c.DeathBlastRadius = Clamp(c.DeathBlastRadius, 0, MaxDeathBlastRadius)
+	c.DeathBlastDamage = min(max(c.DeathBlastDamage, 0), PlayerMaxHP)
+	if c.CatchUpSteps <= 0 {
+		c.CatchUpSteps = DefaultCatchUpSteps
+	}
+	c.CatchUpSteps = min(c.CatchUpSteps, MaxCatchUpSteps)
+	return c
+}
+
+// spawnInterval fills in and clamps a MatchConfig spawn interval
+func spawnInterval(v, def float64) float64 {
+	if v <= 0 {
+		return def
+	}
+	return Clamp(v, MinSpawnInterval, MaxSpawnInterval)
+}
+
+// entityCap fills in and clamps a MatchConfig entity cap
+func entityCap(v, def, limit int) int {
+	if v <= 0 {
+		return def
+	}
+	return min(v, limit)
+}
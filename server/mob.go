@@ -31,7 +31,7 @@ const (
 	MobOptimalRangeSq = MobOptimalRange * MobOptimalRange
 	MobDodgeRange     = 300.0 // range to detect incoming projectiles
 	MobDodgeRangeSq   = MobDodgeRange * MobDodgeRange
-	MobDodgeImpulse   = 120.0 // lateral velocity impulse when dodging
+	MobDodgeImpulse   = 120.0 // lateral velocity change per dodge (one-off, so not scaled by dt)
 	MobDodgeCooldown  = 0.3   // seconds between dodge reactions
 	MobStrafeFlipMin  = 1.5   // min seconds before strafe direction flip
 	MobStrafeFlipMax  = 3.5   // max seconds before strafe direction flip
@@ -55,9 +55,14 @@ const (
 
 	// Spawn chance: 1/15 Star Destroyer, 14/15 TIE
 	SDSpawnChance = 1.0 / 15.0
+
+	// Difficulty scaling with session size: +10% HP per player beyond the
+	// first, with acceleration and burst size growing at half that rate
+	MobDifficultyPerPlayer = 0.10
+	MaxMobDifficulty       = 2.0
 )
 
-// Mob phrase pools keyed by situation
+// Mob phrase pools keyed by situation (built-in defaults, see LoadPhrasePack)
 var mobPhrases = map[string][]string{
 	"notice": {
 		"🎯 Target acquired!",
@@ -127,6 +132,7 @@ type Mob struct {
 	Radius      float64
 	ProjOffset  float64
 	Alive       bool
+	Wrapped     bool    // crossed a world edge since the last state broadcast
 	BurstLeft   int     // shots remaining in current burst
 	FireCD      float64 // cooldown between individual shots
 	BurstCD     float64 // cooldown between bursts
@@ -136,11 +142,23 @@ type Mob struct {
 	StrafeDir   float64 // +1 or -1 for circle strafe direction
 	StrafeTimer float64 // timer until strafe direction flip
 	DodgeCD     float64 // cooldown for dodge reactions
+	Aggression  float64 // range/fire-rate scaler from MatchConfig (0 = default)
+	TargetID    string  // player currently tracked ("" when wandering)
 
 	// State tracking for phrases
 	WasTracking  bool   // was tracking a player last tick
 	SaidLowHP    bool   // already said low-HP phrase
 	PendingPhrase string // phrase to broadcast this tick
+
+	rng randSource // session source for AI jitter (nil = globalRand)
+}
+
+// source returns the randomness the mob's AI draws from
+func (m *Mob) source() randSource {
+	if m.rng == nil {
+		return globalRand{}
+	}
+	return m.rng
 }
 
 // pickPhrase randomly selects a phrase from a pool (with chance gate)
@@ -148,7 +166,7 @@ func pickPhrase(pool string, chance float64) string {
 	if rand.Float64() > chance {
 		return ""
 	}
-	phrases := mobPhrases[pool]
+	phrases := currentPhrases()[pool]
 	if len(phrases) == 0 {
 		return ""
 	}
@@ -157,7 +175,7 @@ func pickPhrase(pool string, chance float64) string {
 
 // pickPhraseAlways selects a phrase without chance gate
 func pickPhraseAlways(pool string) string {
-	phrases := mobPhrases[pool]
+	phrases := currentPhrases()[pool]
 	if len(phrases) == 0 {
 		return ""
 	}
@@ -166,18 +184,52 @@ func pickPhraseAlways(pool string) string {
 
 // NewMob spawns a random mob type at a random map edge
 func NewMob() *Mob {
-	if rand.Float64() < SDSpawnChance {
-		return NewStarDestroyerMob()
+	return NewMobScaled(1)
+}
+
+// NewMobScaled spawns a random mob with stats scaled by difficulty (1 = base)
+func NewMobScaled(difficulty float64) *Mob {
+	m := newMobFrom(globalRand{}, MobWeights{})
+	m.applyDifficulty(difficulty)
+	return m
+}
+
+// MobDifficulty returns the mob difficulty for a session with n connected
+// human players
+func MobDifficulty(n int) float64 {
+	return Clamp(1+float64(n-1)*MobDifficultyPerPlayer, 1, MaxMobDifficulty)
+}
+
+// applyDifficulty scales a fresh mob's HP, acceleration and burst size
+func (m *Mob) applyDifficulty(d float64) {
+	if d <= 1 {
+		return
 	}
-	return NewTieMob()
+	m.MaxHP = int(math.Round(float64(m.MaxHP) * d))
+	m.HP = m.MaxHP
+	half := 1 + (d-1)/2
+	m.Accel *= half
+	m.BurstSize = int(math.Round(float64(m.BurstSize) * half))
+}
+
+// newMobFrom is NewMob drawing from r, picking the archetype by w
+func newMobFrom(r randSource, w MobWeights) *Mob {
+	if r.Float64() < w.sdChance() {
+		return newStarDestroyerMob(r)
+	}
+	return newTieMob(r)
 }
 
 // NewTieMob spawns a TIE fighter mob (regular)
 func NewTieMob() *Mob {
-	m := newBaseMob()
+	return newTieMob(globalRand{})
+}
+
+func newTieMob(r randSource) *Mob {
+	m := newBaseMob(r)
 	m.HP = TieMaxHP
 	m.MaxHP = TieMaxHP
-	m.ShipType = 4 + rand.Intn(2) // type 4 or 5
+	m.ShipType = 4 + r.Intn(2) // type 4 or 5
 	m.MaxSpeed = TieSpeed
 	m.TurnSpeed = TieTurnSpeed
 	m.Accel = TieAccel
@@ -191,7 +243,11 @@ func NewTieMob() *Mob {
 
 // NewStarDestroyerMob spawns a Star Destroyer mob (elite: 5x HP, 3x damage, 3x slower)
 func NewStarDestroyerMob() *Mob {
-	m := newBaseMob()
+	return newStarDestroyerMob(globalRand{})
+}
+
+func newStarDestroyerMob(r randSource) *Mob {
+	m := newBaseMob(r)
 	m.HP = SDMaxHP
 	m.MaxHP = SDMaxHP
 	m.ShipType = 3
@@ -207,7 +263,7 @@ func NewStarDestroyerMob() *Mob {
 }
 
 // newBaseMob creates a mob with shared setup (position, rotation, strafe)
-func newBaseMob() *Mob {
+func newBaseMob(r randSource) *Mob {
 	id := GenerateID(4)
 	m := &Mob{
 		ID:    id,
@@ -215,19 +271,19 @@ func newBaseMob() *Mob {
 	}
 
 	// Pick a random edge: 0=left, 1=right, 2=top, 3=bottom
-	edge := int(randFloat() * 4)
+	edge := int(r.Float64() * 4)
 	switch edge {
 	case 0: // left
 		m.X = 0
-		m.Y = randFloat() * WorldHeight
+		m.Y = r.Float64() * WorldHeight
 	case 1: // right
 		m.X = WorldWidth
-		m.Y = randFloat() * WorldHeight
+		m.Y = r.Float64() * WorldHeight
 	case 2: // top
-		m.X = randFloat() * WorldWidth
+		m.X = r.Float64() * WorldWidth
 		m.Y = 0
 	default: // bottom
-		m.X = randFloat() * WorldWidth
+		m.X = r.Float64() * WorldWidth
 		m.Y = WorldHeight
 	}
 
@@ -236,18 +292,26 @@ func newBaseMob() *Mob {
 	m.WanderAngle = m.Rotation
 
 	// Random strafe direction
-	if rand.Float64() < 0.5 {
+	if r.Float64() < 0.5 {
 		m.StrafeDir = 1
 	} else {
 		m.StrafeDir = -1
 	}
-	m.StrafeTimer = MobStrafeFlipMin + rand.Float64()*(MobStrafeFlipMax-MobStrafeFlipMin)
+	m.StrafeTimer = MobStrafeFlipMin + r.Float64()*(MobStrafeFlipMax-MobStrafeFlipMin)
 	return m
 }
 
+// aggression returns the mob's aggression scaler, defaulting to 1
+func (m *Mob) aggression() float64 {
+	if m.Aggression <= 0 {
+		return 1.0
+	}
+	return m.Aggression
+}
+
 // Update moves the mob and steers toward nearest player or center.
 // Returns true if the mob wants to fire this tick.
-func (m *Mob) Update(dt float64, players map[string]*Player, projectiles map[string]*Projectile) bool {
+func (m *Mob) Update(dt float64, players map[string]*Player, projectiles []*Projectile) bool {
 	if !m.Alive {
 		return false
 	}
@@ -263,23 +327,33 @@ func (m *Mob) Update(dt float64, players map[string]*Player, projectiles map[str
 		m.DodgeCD -= dt
 	}
 
+	// Ranges and fire rate scale with aggression
+	aggr := m.aggression()
+	detectRangeSq := MobDetectRangeSq * aggr * aggr
+	shootRangeSq := MobShootRangeSq * aggr * aggr
+	optimalRange := MobOptimalRange * aggr
+	burstFireRate := MobBurstFireRate / aggr
+	burstCooldown := MobBurstCooldown / aggr
+
 	// Find nearest alive player within detect range (also capture velocity for lead targeting)
 	var targetX, targetY, targetVX, targetVY float64
 	bestDist := math.MaxFloat64
 	found := false
+	m.TargetID = ""
 
 	for _, p := range players {
 		if !p.Alive {
 			continue
 		}
 		d2 := DistanceSq(m.X, m.Y, p.X, p.Y)
-		if d2 < MobDetectRangeSq && d2 < bestDist {
+		if d2 < detectRangeSq && d2 < bestDist {
 			bestDist = d2
 			targetX = p.X
 			targetY = p.Y
 			targetVX = p.VX
 			targetVY = p.VY
 			found = true
+			m.TargetID = p.ID
 		}
 	}
 
@@ -313,7 +387,7 @@ func (m *Mob) Update(dt float64, players map[string]*Player, projectiles map[str
 		// --- OPTIMAL DISTANCE + CIRCLE STRAFE: compute movement direction ---
 		angleToTarget := math.Atan2(targetY-m.Y, targetX-m.X)
 		// radial: +1 = approach, -1 = retreat
-		radial := Clamp((dist-MobOptimalRange)/(MobOptimalRange*0.5), -1, 1)
+		radial := Clamp((dist-optimalRange)/(optimalRange*0.5), -1, 1)
 		// tangential: strafe more when near optimal range
 		tangential := m.StrafeDir * (1.0 - math.Abs(radial)*0.7)
 		moveX := math.Cos(angleToTarget)*radial + math.Cos(angleToTarget+math.Pi/2)*tangential
@@ -324,7 +398,7 @@ func (m *Mob) Update(dt float64, players map[string]*Player, projectiles map[str
 		m.StrafeTimer -= dt
 		if m.StrafeTimer <= 0 {
 			m.StrafeDir = -m.StrafeDir
-			m.StrafeTimer = MobStrafeFlipMin + rand.Float64()*(MobStrafeFlipMax-MobStrafeFlipMin)
+			m.StrafeTimer = MobStrafeFlipMin + m.source().Float64()*(MobStrafeFlipMax-MobStrafeFlipMin)
 		}
 
 		// Accelerate in movement direction (decoupled from aim)
@@ -339,7 +413,7 @@ func (m *Mob) Update(dt float64, players map[string]*Player, projectiles map[str
 		m.WasTracking = false
 
 		// Wander: drift the wander angle gently, then turn toward it
-		m.WanderAngle += (randFloat()*2 - 1) * MobWanderDrift * dt
+		m.WanderAngle += (m.source().Float64()*2 - 1) * MobWanderDrift * dt
 		diff := NormalizeAngle(m.WanderAngle - m.Rotation)
 		maxTurn := MobWanderTurn * dt
 		if diff > maxTurn {
@@ -356,8 +430,9 @@ func (m *Mob) Update(dt float64, players map[string]*Player, projectiles map[str
 	}
 
 	// Friction
-	m.VX *= MobFriction
-	m.VY *= MobFriction
+	friction := Damping(MobFriction, dt)
+	m.VX *= friction
+	m.VY *= friction
 
 	// Clamp speed
 	speed := math.Sqrt(m.VX*m.VX + m.VY*m.VY)
@@ -424,25 +499,29 @@ func (m *Mob) Update(dt float64, players map[string]*Player, projectiles map[str
 	// Wrap around world edges
 	if m.X < 0 {
 		m.X += WorldWidth
+		m.Wrapped = true
 	} else if m.X > WorldWidth {
 		m.X -= WorldWidth
+		m.Wrapped = true
 	}
 	if m.Y < 0 {
 		m.Y += WorldHeight
+		m.Wrapped = true
 	} else if m.Y > WorldHeight {
 		m.Y -= WorldHeight
+		m.Wrapped = true
 	}
 
 	// Burst fire logic
 	wantFire := false
-	if found && bestDist < MobShootRangeSq {
+	if found && bestDist < shootRangeSq {
 		if m.BurstLeft > 0 && m.FireCD <= 0 {
 			// Continue burst
 			wantFire = true
 			m.BurstLeft--
-			m.FireCD = MobBurstFireRate
+			m.FireCD = burstFireRate
 			if m.BurstLeft == 0 {
-				m.BurstCD = MobBurstCooldown
+				m.BurstCD = burstCooldown
 			}
 		} else if m.BurstLeft == 0 && m.BurstCD <= 0 {
 			// Start new burst — say fire phrase
@@ -452,9 +531,9 @@ func (m *Mob) Update(dt float64, players map[string]*Player, projectiles map[str
 			m.BurstLeft = m.BurstSize
 			wantFire = true
 			m.BurstLeft--
-			m.FireCD = MobBurstFireRate
+			m.FireCD = burstFireRate
 			if m.BurstLeft == 0 {
-				m.BurstCD = MobBurstCooldown
+				m.BurstCD = burstCooldown
 			}
 		}
 	}
@@ -496,5 +575,6 @@ func (m *Mob) ToState() MobState {
 		MaxHP: m.MaxHP,
 		Ship:  m.ShipType,
 		Alive: m.Alive,
+		Wrap:  m.Wrapped,
 	}
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"math"
+	"math/rand"
 	"testing"
 )
 
@@ -46,6 +47,46 @@ func TestMobTakeDamage(t *testing.T) {
 	}
 }
 
+func TestMobDifficultyScaling(t *testing.T) {
+	if d := MobDifficulty(1); d != 1 {
+		t.Errorf("solo difficulty should be 1, got %f", d)
+	}
+	if d := MobDifficulty(50); d != MaxMobDifficulty {
+		t.Errorf("difficulty should cap at %f, got %f", MaxMobDifficulty, d)
+	}
+
+	m := NewTieMob()
+	m.applyDifficulty(MobDifficulty(6)) // 1.5x
+	if m.MaxHP != 90 || m.HP != 90 {
+		t.Errorf("expected 90 HP at 1.5x, got %d/%d", m.HP, m.MaxHP)
+	}
+	if m.Accel <= TieAccel || m.BurstSize <= TieBurstSize {
+		t.Errorf("accel and burst should grow, got %f / %d", m.Accel, m.BurstSize)
+	}
+}
+
+func TestMobWeightsSkewArchetypes(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	bossRush := MobWeights{Tie: 1, StarDestroyer: 9}
+	sds := 0
+	const spawns = 500
+	for i := 0; i < spawns; i++ {
+		if newMobFrom(r, bossRush).Radius == SDRadius {
+			sds++
+		}
+	}
+	if sds < spawns*8/10 {
+		t.Errorf("boss-rush weights should spawn mostly Star Destroyers, got %d/%d", sds, spawns)
+	}
+
+	if (MobWeights{Tie: -1}).Valid() {
+		t.Error("negative weights should be invalid")
+	}
+	if c := (MobWeights{}).sdChance(); c != SDSpawnChance {
+		t.Errorf("zero weights should use the default mix, got %f", c)
+	}
+}
+
 func TestMobTakeDamageWhenDead(t *testing.T) {
 	m := NewMob()
 	m.Alive = false
@@ -72,7 +113,7 @@ func TestMobAISteersTowardPlayer(t *testing.T) {
 
 	// Run a few updates
 	for i := 0; i < 60; i++ {
-		m.Update(1.0/60.0, players, make(map[string]*Projectile))
+		m.Update(1.0/60.0, players, nil)
 	}
 
 	// Mob should have moved toward the player (rightward)
@@ -93,7 +134,7 @@ func TestMobAIWandersWhenIdle(t *testing.T) {
 
 	startX, startY := m.X, m.Y
 	for i := 0; i < 120; i++ {
-		m.Update(1.0/60.0, players, make(map[string]*Projectile))
+		m.Update(1.0/60.0, players, nil)
 	}
 
 	// Mob should have moved from its starting position
@@ -104,3 +145,74 @@ func TestMobAIWandersWhenIdle(t *testing.T) {
 		t.Errorf("mob should wander when idle, only moved %f", dist)
 	}
 }
+
+func TestMobAggressionExtendsDetectRange(t *testing.T) {
+	// Player just outside the default detect range
+	players := map[string]*Player{
+		"p1": {ID: "p1", X: 2000 + MobDetectRange + 50, Y: 2000, Alive: true},
+	}
+
+	calm := NewTieMob()
+	calm.X, calm.Y = 2000, 2000
+	calm.Update(1.0/60.0, players, nil)
+	if calm.WasTracking {
+		t.Error("default-aggression mob should not detect a player beyond MobDetectRange")
+	}
+
+	aggro := NewTieMob()
+	aggro.X, aggro.Y = 2000, 2000
+	aggro.Aggression = 2.0
+	aggro.Update(1.0/60.0, players, nil)
+	if !aggro.WasTracking {
+		t.Error("high-aggression mob should detect a player beyond MobDetectRange")
+	}
+}
+
+func TestMobAggressionFiresFromFarther(t *testing.T) {
+	// Player outside default shoot range
+	players := map[string]*Player{
+		"p1": {ID: "p1", X: 2000 + MobShootRange + 50, Y: 2000, Alive: true},
+	}
+
+	calm := NewTieMob()
+	calm.X, calm.Y = 2000, 2000
+	if calm.Update(1.0/60.0, players, nil) {
+		t.Error("default-aggression mob should not fire beyond MobShootRange")
+	}
+
+	aggro := NewTieMob()
+	aggro.X, aggro.Y = 2000, 2000
+	aggro.Aggression = 2.0
+	if !aggro.Update(1.0/60.0, players, nil) {
+		t.Error("high-aggression mob should fire beyond MobShootRange")
+	}
+	if aggro.FireCD >= MobBurstFireRate {
+		t.Errorf("high-aggression mob should fire faster, FireCD=%f", aggro.FireCD)
+	}
+}
+
+func TestMatchConfigSanitizeAggression(t *testing.T) {
+	cfg := MatchConfig{}.Sanitize()
+	if cfg.MobAggression != 1.0 {
+		t.Errorf("zero aggression should default to 1, got %f", cfg.MobAggression)
+	}
+	cfg = MatchConfig{MobAggression: 100}.Sanitize()
+	if cfg.MobAggression != MaxMobAggression {
+		t.Errorf("aggression should clamp to %f, got %f", MaxMobAggression, cfg.MobAggression)
+	}
+}
+
+func TestGameAppliesMobAggression(t *testing.T) {
+	g := NewGameWithConfig(MatchConfig{MobAggression: 2.0})
+	g.AddPlayer("Solo")
+	g.mobSpawnCD = 0
+	g.spawnEntities(1.0 / 60.0)
+	for _, m := range g.mobs {
+		if m.Aggression != 2.0 {
+			t.Errorf("spawned mob should inherit aggression 2.0, got %f", m.Aggression)
+		}
+	}
+	if len(g.mobs) == 0 {
+		t.Fatal("expected a mob to spawn")
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+const (
+	StaticAsteroidMinRadius = 40.0
+	StaticAsteroidMaxRadius = 90.0
+	MaxStaticAsteroids      = 40
+	staticAsteroidGap       = 120.0 // min clearance between static asteroids, so ships fit through
+	staticAsteroidMargin    = 200.0 // keep clear of the world edges
+	staticAsteroidTries     = 30    // placement attempts per asteroid before giving up
+	staticSeedSalt          = 0x5a17c0de
+)
+
+// Obstacle is a static asteroid: permanent cover that blocks ships and shots
+type Obstacle struct {
+	ID   string
+	X, Y float64
+	R    float64
+}
+
+// ToState converts to protocol state
+func (o *Obstacle) ToState() ObstacleState {
+	return ObstacleState{ID: o.ID, X: o.X, Y: o.Y, R: o.R}
+}
+
+// PlaceStaticAsteroids lays out up to count static asteroids from seed.
+// The same seed and count always give the same field.
+func PlaceStaticAsteroids(seed int64, count int) []Obstacle {
+	count = min(count, MaxStaticAsteroids)
+	if count <= 0 {
+		return nil
+	}
+	// Own source so the field doesn't shift the seed's spawn patterns
+	r := rand.New(rand.NewSource(seed ^ staticSeedSalt))
+	field := make([]Obstacle, 0, count)
+	for len(field) < count {
+		placed := false
+		for try := 0; try < staticAsteroidTries && !placed; try++ {
+			o := Obstacle{
+				ID: fmt.Sprintf("s%d", len(field)),
+				X:  staticAsteroidMargin + r.Float64()*(WorldWidth-2*staticAsteroidMargin),
+				Y:  staticAsteroidMargin + r.Float64()*(WorldHeight-2*staticAsteroidMargin),
+				R:  StaticAsteroidMinRadius + r.Float64()*(StaticAsteroidMaxRadius-StaticAsteroidMinRadius),
+			}
+			placed = true
+			for i := range field {
+				if Distance(o.X, o.Y, field[i].X, field[i].Y) < o.R+field[i].R+staticAsteroidGap {
+					placed = false
+					break
+				}
+			}
+			if placed {
+				field = append(field, o)
+			}
+		}
+		if !placed {
+			break // world is full at this density
+		}
+	}
+	return field
+}
+
+// pushOut moves a circle at (x, y) with radius r out of the obstacle and
+// cancels its velocity into it. Returns false if they didn't overlap.
+func (o *Obstacle) pushOut(x, y, vx, vy *float64, r float64) bool {
+	dx := *x - o.X
+	dy := *y - o.Y
+	minDist := o.R + r
+	d2 := dx*dx + dy*dy
+	if d2 >= minDist*minDist {
+		return false
+	}
+	dist := math.Sqrt(d2)
+	nx, ny := 1.0, 0.0
+	if dist > 0.001 {
+		nx, ny = dx/dist, dy/dist
+	}
+	*x = o.X + nx*minDist
+	*y = o.Y + ny*minDist
+	if into := *vx*nx + *vy*ny; into < 0 {
+		*vx -= into * nx
+		*vy -= into * ny
+	}
+	return true
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStaticAsteroidsPlacedFromSeed(t *testing.T) {
+	a := PlaceStaticAsteroids(99, 12)
+	b := PlaceStaticAsteroids(99, 12)
+	if len(a) != 12 {
+		t.Fatalf("expected 12 static asteroids, got %d", len(a))
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("same seed should place identically: %+v vs %+v", a[i], b[i])
+		}
+	}
+	if c := PlaceStaticAsteroids(100, 12); c[0] == a[0] {
+		t.Error("a different seed should place differently")
+	}
+	for i := range a {
+		for j := i + 1; j < len(a); j++ {
+			if Distance(a[i].X, a[i].Y, a[j].X, a[j].Y) < a[i].R+a[j].R+staticAsteroidGap {
+				t.Errorf("static asteroids %d and %d leave no gap", i, j)
+			}
+		}
+	}
+	if PlaceStaticAsteroids(99, 0) != nil {
+		t.Error("zero density should place nothing")
+	}
+
+	g := NewGameWithConfig(MatchConfig{Seed: 99, StaticAsteroids: 12})
+	if len(g.obstacles) != 12 || g.obstacles[3] != a[3] {
+		t.Error("game should place its field from the session seed")
+	}
+}
+
+func TestStaticAsteroidBlocksProjectile(t *testing.T) {
+	g := NewGameWithConfig(MatchConfig{StaticAsteroids: 1})
+	o := &g.obstacles[0]
+	target := g.AddPlayer("Behind")
+	target.X, target.Y = o.X+o.R+60, o.Y
+
+	// Shot from the far side, aimed through the rock at the player
+	proj := &Projectile{ID: "shot", OwnerID: "x", X: o.X - o.R - 20, Y: o.Y,
+		VX: ProjectileSpeed, Life: ProjectileLifetime, Damage: ProjectileDamage, Alive: true}
+	g.projectiles = append(g.projectiles, proj)
+	for i := 0; i < 30 && proj.Alive; i++ {
+		g.update()
+	}
+	if proj.Alive {
+		t.Fatal("projectile should be stopped by the static asteroid")
+	}
+	if target.HP != PlayerMaxHP {
+		t.Errorf("player behind cover took damage: HP %d", target.HP)
+	}
+}
+
+func TestStaticAsteroidBlocksMovement(t *testing.T) {
+	g := NewGameWithConfig(MatchConfig{StaticAsteroids: 1})
+	o := &g.obstacles[0]
+	p := g.AddPlayer("Rammer")
+	p.X, p.Y = o.X-o.R-PlayerRadius-50, o.Y
+	p.VX, p.VY = PlayerMaxSpeed, 0
+	p.Rotation = 0
+
+	for i := 0; i < TickRate; i++ {
+		g.HandleInput(p.ID, ClientInput{MX: o.X + 1000, MY: o.Y, Thresh: 50})
+		g.update()
+		if d := Distance(p.X, p.Y, o.X, o.Y); d < o.R+PlayerRadius-1 {
+			t.Fatalf("tick %d: ship inside static asteroid (dist %.1f, limit %.1f)", i, d, o.R+PlayerRadius)
+		}
+	}
+	if p.X > o.X {
+		t.Errorf("ship should not pass through the static asteroid, x=%.1f obstacle x=%.1f", p.X, o.X)
+	}
+}
+
+func TestStaticAsteroidBlocksDriftingAsteroid(t *testing.T) {
+	g := NewGameWithConfig(MatchConfig{StaticAsteroids: 1, MaxAsteroids: 1})
+	o := &g.obstacles[0]
+	ast := &Asteroid{ID: "rock", X: o.X - o.R - AsteroidRadius - 50, Y: o.Y, VX: AsteroidMaxSpeed, Alive: true}
+	g.asteroids[ast.ID] = ast
+	g.asteroidSpawnCD = 1000
+
+	for i := 0; i < 2*TickRate; i++ {
+		g.update()
+		if d := Distance(ast.X, ast.Y, o.X, o.Y); d < o.R+AsteroidRadius-1 {
+			t.Fatalf("tick %d: asteroid inside static asteroid (dist %.1f)", i, d)
+		}
+	}
+	if ast.X > o.X {
+		t.Errorf("asteroid should not drift through the static asteroid, x=%.1f obstacle x=%.1f", ast.X, o.X)
+	}
+}
+
+func TestSpawnsAvoidStaticAsteroids(t *testing.T) {
+	g := NewGameWithConfig(MatchConfig{Seed: 7, StaticAsteroids: MaxStaticAsteroids, MaxPickups: 1})
+	assertClear := func(what string, x, y, r float64) {
+		t.Helper()
+		for i := range g.obstacles {
+			o := &g.obstacles[i]
+			if CheckCollision(x, y, r, o.X, o.Y, o.R) {
+				t.Fatalf("%s at (%.0f, %.0f) spawned inside static asteroid %s", what, x, y, o.ID)
+			}
+		}
+	}
+
+	for i := 0; i < maxPlayersPerSession; i++ {
+		p := g.AddPlayer("Pilot")
+		assertClear("joining ship", p.X, p.Y, PlayerRadius)
+	}
+
+	// Check the respawn point clients are told about, before the obstacle
+	// pass could push the ship out
+	p := g.orderedPlayers()[0]
+	mock := &mockBroadcaster{}
+	g.SetClient(p.ID, mock)
+	for i := 0; i < 50; i++ {
+		p.Alive, p.RespawnT = false, 0
+		g.update()
+	}
+	teleports := 0
+	for _, raw := range mock.rawMsgs {
+		var env struct {
+			T string      `json:"t"`
+			D TeleportMsg `json:"d"`
+		}
+		if json.Unmarshal(raw, &env) != nil || env.T != MsgTeleport {
+			continue
+		}
+		teleports++
+		assertClear("respawned ship", env.D.X, env.D.Y, PlayerRadius)
+	}
+	if teleports != 50 {
+		t.Fatalf("expected 50 respawn teleports, got %d", teleports)
+	}
+
+	for i := 0; i < 50; i++ {
+		for id := range g.pickups {
+			delete(g.pickups, id)
+		}
+		g.pickupSpawnCD = 0
+		g.spawnEntities(0)
+		for _, pk := range g.pickups {
+			assertClear("pickup", pk.X, pk.Y, PickupRadius)
+		}
+	}
+}
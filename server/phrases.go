@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// activePhrases is the phrase table read by pickPhrase/pickPhraseAlways.
+// It starts as the built-in mobPhrases and can be swapped by LoadPhrasePack.
+var activePhrases atomic.Pointer[map[string][]string]
+
+func init() {
+	SetPhrasePack(nil)
+}
+
+// currentPhrases returns the active phrase table
+func currentPhrases() map[string][]string {
+	return *activePhrases.Load()
+}
+
+// SetPhrasePack installs a custom phrase pack over the built-in phrases.
+// Pools that are missing or empty in the pack keep the built-in phrases.
+func SetPhrasePack(pack map[string][]string) {
+	table := make(map[string][]string, len(mobPhrases))
+	for situation, phrases := range mobPhrases {
+		table[situation] = phrases
+	}
+	for situation, phrases := range pack {
+		cleaned := make([]string, 0, len(phrases))
+		for _, p := range phrases {
+			if strings.TrimSpace(p) != "" {
+				cleaned = append(cleaned, p)
+			}
+		}
+		if len(cleaned) > 0 {
+			table[situation] = cleaned
+		}
+	}
+	activePhrases.Store(&table)
+}
+
+// LoadPhrasePack reads a JSON phrase pack ({"situation": ["phrase", ...]}) from disk.
+// The pack is validated first; on error the active phrases are left unchanged.
+func LoadPhrasePack(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var pack map[string][]string
+	if err := json.Unmarshal(data, &pack); err != nil {
+		return fmt.Errorf("parse phrase pack %s: %w", path, err)
+	}
+	for situation := range pack {
+		if _, ok := mobPhrases[situation]; !ok {
+			return fmt.Errorf("phrase pack %s: unknown situation %q", path, situation)
+		}
+	}
+	SetPhrasePack(pack)
+	return nil
+}
+
+// ReloadPhrases re-reads the mob phrase pack ("" means built-in phrases)
+// into the running server. Running games pick up the new phrases on their
+// next pick. On error nothing is swapped.
+func ReloadPhrases(phrasesPath string) error {
+	if phrasesPath == "" {
+		SetPhrasePack(nil)
+		return nil
+	}
+	return LoadPhrasePack(phrasesPath)
+}
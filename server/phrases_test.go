@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPhrasePackReplacesDefaults(t *testing.T) {
+	defer SetPhrasePack(nil)
+
+	SetPhrasePack(map[string][]string{
+		"notice": {"Ziel erfasst!"},
+	})
+	for i := 0; i < 10; i++ {
+		if got := pickPhraseAlways("notice"); got != "Ziel erfasst!" {
+			t.Fatalf("expected custom phrase, got %q", got)
+		}
+	}
+	// Pools not in the pack keep the built-in phrases
+	if got := pickPhraseAlways("lost"); got == "" {
+		t.Error("pool missing from pack should fall back to built-in phrases")
+	}
+}
+
+func TestPhrasePackEmptyPoolFallsBack(t *testing.T) {
+	defer SetPhrasePack(nil)
+
+	SetPhrasePack(map[string][]string{
+		"fire":   {},
+		"low_hp": {"", "   "},
+	})
+	if got := pickPhraseAlways("fire"); got == "" {
+		t.Error("empty pool should fall back to built-in phrases")
+	}
+	if got := pickPhraseAlways("low_hp"); got == "" {
+		t.Error("pool of blank phrases should fall back to built-in phrases")
+	}
+}
+
+func TestLoadPhrasePackFromFile(t *testing.T) {
+	defer SetPhrasePack(nil)
+
+	path := filepath.Join(t.TempDir(), "phrases.json")
+	os.WriteFile(path, []byte(`{"kill_player": ["GG"]}`), 0o644)
+	if err := LoadPhrasePack(path); err != nil {
+		t.Fatalf("load phrase pack: %v", err)
+	}
+	if got := pickPhraseAlways("kill_player"); got != "GG" {
+		t.Errorf("expected phrase from file, got %q", got)
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.json")
+	os.WriteFile(bad, []byte(`not json`), 0o644)
+	if err := LoadPhrasePack(bad); err == nil {
+		t.Error("expected error for malformed phrase pack")
+	}
+	if got := pickPhraseAlways("kill_player"); got != "GG" {
+		t.Error("failed load should keep the previously loaded pack")
+	}
+}
+
+func TestReloadPhrasesSwapsPhrases(t *testing.T) {
+	defer SetPhrasePack(nil)
+
+	path := filepath.Join(t.TempDir(), "phrases.json")
+	os.WriteFile(path, []byte(`{"notice": ["first"]}`), 0o644)
+	if err := ReloadPhrases(path); err != nil {
+		t.Fatalf("initial load: %v", err)
+	}
+
+	os.WriteFile(path, []byte(`{"notice": ["second"]}`), 0o644)
+	if err := ReloadPhrases(path); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if got := pickPhraseAlways("notice"); got != "second" {
+		t.Errorf("expected reloaded phrase, got %q", got)
+	}
+
+	// A pack with a typo'd situation is rejected and the live pack kept
+	os.WriteFile(path, []byte(`{"notcie": ["typo"]}`), 0o644)
+	if err := ReloadPhrases(path); err == nil {
+		t.Error("expected unknown situation to be rejected")
+	}
+	if got := pickPhraseAlways("notice"); got != "second" {
+		t.Errorf("rejected reload should keep the live pack, got %q", got)
+	}
+}
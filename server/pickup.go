@@ -1,9 +1,21 @@
 package main
 
+import "math"
+
 const (
 	PickupRadius  = 15.0
 	PickupHeal    = 20
 	PickupTimeout = 30.0
+
+	MaxPickupMagnetRadius = 600.0
+	PickupMagnetSpeed     = 250.0 // pixels/s a magnetized pickup drifts toward its player
+	PickupLowHP           = 0.5   // HP fraction at or below which pickup perks (magnet, shield) apply
+
+	MaxPickupShieldTime          = 5.0
+	DefaultPickupShieldReduction = 0.5
+
+	MaxOvershield   = 100
+	OvershieldDecay = 4.0 // overshield points lost per second
 )
 
 // Pickup is a health orb that heals on contact
@@ -16,15 +28,43 @@ type Pickup struct {
 
 // NewPickup spawns a pickup at a random position away from edges
 func NewPickup() *Pickup {
+	return newPickupFrom(globalRand{})
+}
+
+// newPickupFrom is NewPickup drawing from r
+func newPickupFrom(r randSource) *Pickup {
+	x, y := pickupPoint(r)
 	return &Pickup{
 		ID:    GenerateID(4),
-		X:     50 + randFloat()*3900,
-		Y:     50 + randFloat()*3900,
+		X:     x,
+		Y:     y,
 		Life:  PickupTimeout,
 		Alive: true,
 	}
 }
 
+// pickupPoint picks a pickup position away from the edges
+func pickupPoint(r randSource) (x, y float64) {
+	return 50 + r.Float64()*3900, 50 + r.Float64()*3900
+}
+
+// PullToward drifts the pickup up to step pixels toward (x, y) without overshooting
+func (p *Pickup) PullToward(x, y, step float64) {
+	dx, dy := x-p.X, y-p.Y
+	d := math.Sqrt(dx*dx + dy*dy)
+	if d <= step {
+		p.X, p.Y = x, y
+		return
+	}
+	p.X += dx / d * step
+	p.Y += dy / d * step
+}
+
+// lowHP reports whether p is hurt enough for pickup perks
+func lowHP(p *Player) bool {
+	return float64(p.HP) <= float64(p.MaxHP)*PickupLowHP
+}
+
 // Update ticks down the pickup lifetime
 func (p *Pickup) Update(dt float64) {
 	if !p.Alive {
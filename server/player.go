@@ -1,44 +1,102 @@
 package main
 
 import (
-	"crypto/rand"
 	"math"
+	mrand "math/rand"
 )
 
 const (
 	PlayerRadius     = 25.0
 	PlayerMaxHP      = 100
-	PlayerAccel      = 600.0  // pixels/s²
-	PlayerMaxSpeed   = 350.0  // pixels/s
-	PlayerFriction   = 0.97   // velocity multiplier per tick
-	PlayerBoostMul   = 1.6    // boost speed multiplier
-	FireCooldown     = 0.15   // seconds between shots
-	RespawnTime      = 3.0    // seconds before respawn
+	PlayerAccel      = 600.0 // pixels/s²
+	PlayerMaxSpeed   = 350.0 // pixels/s
+	PlayerFriction   = 0.97  // velocity multiplier per tick
+	PlayerBoostMul   = 1.6   // boost speed multiplier
+	MaxBoostMul      = 3.0
+	MaxBoostStamina  = 30.0 // seconds
+	BoostRegenRate   = 0.5  // stamina seconds regained per second off boost
+	FireCooldown     = 0.15 // seconds between shots
+	RespawnTime      = 3.0  // seconds before respawn
+	DoubleKillWindow = 4.0  // max seconds between kills to count as a double kill
 	WorldWidth       = 4000.0
 	WorldHeight      = 4000.0
-	TurnSpeed        = 8.0    // radians/s max turn rate
+	TurnSpeed        = 8.0 // radians/s max turn rate
+	TurnAssistAngle  = 0.6 // aim error (radians) above which turn assist kicks in
+	TurnAssistMul    = 2.5 // turn rate multiplier for large aim errors
+
+	StreakBonusFrom  = 5 // kills in a streak before each kill scores StreakBonusScore extra
+	StreakBonusScore = 2
+
+	KillCamFrames      = 20 // recent positions kept per player for the kill-cam
+	KillCamSampleEvery = 6  // ticks between kill-cam samples (10Hz at 60Hz tick)
 )
 
 // Player represents a player in the game
 type Player struct {
-	ID       string
-	Name     string
-	X, Y     float64
-	VX, VY   float64
-	Rotation float64
-	HP       int
-	MaxHP    int
-	ShipType int
-	Score    int
-	Alive    bool
-	FireCD   float64 // fire cooldown remaining
-	RespawnT float64 // respawn timer remaining
-	TargetR  float64 // target rotation (toward mouse)
-	Firing   bool
-	Boosting bool
-	TargetX   float64 // mouse world X (for distance calc)
-	TargetY   float64 // mouse world Y (for distance calc)
-	SlowThresh float64 // distance threshold for speed modulation
+	ID              string
+	Name            string
+	X, Y            float64
+	VX, VY          float64
+	Rotation        float64
+	HP              int
+	MaxHP           int
+	ShipType        int
+	Score           int
+	Alive           bool
+	FireCD          float64 // fire cooldown remaining
+	RespawnT        float64 // respawn timer remaining
+	TargetR         float64 // target rotation (toward mouse)
+	Firing          bool
+	Boosting        bool
+	Boost           BoostTuning // session boost feel (zero value = default)
+	Stamina         float64     // boost seconds left when Boost.Stamina is set
+	boosted         bool        // boost actually applied last Update
+	TurnAssist      bool        // turn faster toward far-off aim (controllers, or MatchConfig.TurnAssist)
+	Wrapped         bool        // crossed a world edge since the last state broadcast
+	TargetX         float64     // mouse world X (for distance calc)
+	TargetY         float64     // mouse world Y (for distance calc)
+	SlowThresh      float64     // distance threshold for speed modulation
+	ShieldT         float64     // pickup shield time remaining
+	ShieldReduction float64     // fraction of damage the pickup shield blocks
+	Overshield      float64     // decaying bonus HP from overhealing, absorbed first
+	KilledBy        string      // player who last killed this one
+	LastHitBy       string      // player who last damaged this one (this life)
+	RejoinToken     string      // secret a dropped client presents to reclaim this ship
+	Disconnected    bool        // client dropped; ship held for the rejoin grace with input frozen
+	disconnectSeq   uint64
+	Following       string     // player whose view is streamed while dead (spectate-on-death)
+	TieredUpdates   bool       // client accepts distance-tiered state updates
+	CullDist        float64    // viewport culling distance (0 = DefaultCullDist)
+	delta           *deltaView // set while the client takes delta frames
+	sent            sentView   // entities in the client's previous frame (see broadcastState)
+	IsBot           bool       // AI pilot added by the host (see bot.go)
+	brain           *botBrain  // bot steering state
+	rng             randSource // session source for respawn points (nil = globalRand)
+	IdleT           float64    // seconds since the last input (see checkIdle)
+	rams            []float64  // game times this player rammed another ship (see noteRam)
+	idleWarned      bool
+	DebugFeed       bool // receives MsgDebugState (only when the server allows it)
+
+	// Combat stats (server-calculated)
+	Kills       int
+	Deaths      int
+	ShotsFired  int
+	ShotsHit    int
+	DamageDealt int
+
+	// Highlight records
+	LongestKill    float64 // farthest shooter-victim distance at a killing hit (px)
+	BestLifeDamage int     // most damage dealt within one life
+	FastestDouble  float64 // shortest gap between two kills in seconds (0 = none yet)
+	lifeDamage     int
+	lastKillAt     float64 // game time of the previous kill
+	Streak         int     // kills since the last death
+
+	// Kill-cam ring buffer of recent positions
+	camFrames [KillCamFrames]KillCamFrame
+	camHead   int
+	camLen    int
+	camFired  bool // fired since the last sample
 }
 
 // NewPlayer creates a new player at a random position
@@ -68,6 +126,11 @@ func (p *Player) Update(dt float64) {
 	// Rotate toward target
 	diff := NormalizeAngle(p.TargetR - p.Rotation)
 	maxTurn := TurnSpeed * dt
+	if p.TurnAssist && math.Abs(diff) > TurnAssistAngle {
+		// Snap toward a far-off aim point, but never past the assist
+		// threshold, so fine aiming near the target stays at TurnSpeed
+		maxTurn = min(maxTurn*TurnAssistMul, math.Abs(diff)-TurnAssistAngle+maxTurn)
+	}
 	if diff > maxTurn {
 		diff = maxTurn
 	} else if diff < -maxTurn {
@@ -76,9 +139,11 @@ func (p *Player) Update(dt float64) {
 	p.Rotation += diff
 
 	// Accelerate in facing direction
+	boostSpeed, boostAccel := p.Boost.mults()
+	p.boosted = p.spendBoost(dt)
 	accel := PlayerAccel * dt
-	if p.Boosting {
-		accel *= PlayerBoostMul
+	if p.boosted {
+		accel *= boostAccel
 	}
 
 	// Distance-based speed modulation: slow down as pointer approaches ship
@@ -108,13 +173,14 @@ func (p *Player) Update(dt float64) {
 		// Blend between brake (0.95) and normal friction based on speedFactor
 		friction = 0.95 + speedFactor*(PlayerFriction-0.95)
 	}
+	friction = Damping(friction, dt)
 	p.VX *= friction
 	p.VY *= friction
 
 	// Clamp speed
 	maxSpd := PlayerMaxSpeed
-	if p.Boosting {
-		maxSpd *= PlayerBoostMul
+	if p.boosted {
+		maxSpd *= boostSpeed
 	}
 	speed := math.Sqrt(p.VX*p.VX + p.VY*p.VY)
 	if speed > maxSpd {
@@ -130,31 +196,101 @@ func (p *Player) Update(dt float64) {
 	// Wrap around world edges
 	if p.X < 0 {
 		p.X += WorldWidth
+		p.Wrapped = true
 	} else if p.X > WorldWidth {
 		p.X -= WorldWidth
+		p.Wrapped = true
 	}
 	if p.Y < 0 {
 		p.Y += WorldHeight
+		p.Wrapped = true
 	} else if p.Y > WorldHeight {
 		p.Y -= WorldHeight
+		p.Wrapped = true
 	}
 
 	// Cooldown
 	if p.FireCD > 0 {
 		p.FireCD -= dt
 	}
+	if p.ShieldT > 0 {
+		p.ShieldT -= dt
+	}
+	if p.Overshield > 0 {
+		p.Overshield = max(p.Overshield-OvershieldDecay*dt, 0)
+	}
+}
+
+// spawnPoint picks a spawn or respawn position in the middle of the world
+func spawnPoint(r randSource) (x, y float64) {
+	return WorldWidth/4 + r.Float64()*WorldWidth/2, WorldHeight/4 + r.Float64()*WorldHeight/2
 }
 
 // Respawn resets the player after death
 func (p *Player) Respawn() {
-	p.X = WorldWidth/4 + randFloat()*WorldWidth/2
-	p.Y = WorldHeight/4 + randFloat()*WorldHeight/2
+	p.X, p.Y = spawnPoint(p.source())
 	p.VX = 0
 	p.VY = 0
 	p.HP = PlayerMaxHP
 	p.Alive = true
 	p.FireCD = 0
 	p.RespawnT = 0
+	p.camLen = 0
+	p.lifeDamage = 0
+	p.ShieldT = 0
+	p.Overshield = 0
+	p.Following = ""
+	p.LastHitBy = ""
+	p.Streak = 0
+	p.Stamina = p.Boost.Stamina
+}
+
+// BoostTuning sets how boosting feels in a session
+type BoostTuning struct {
+	SpeedMul float64 // top speed multiplier while boosting (0 = PlayerBoostMul)
+	AccelMul float64 // thrust multiplier while boosting (0 = SpeedMul)
+	Stamina  float64 // seconds of boost on a full tank (0 = unlimited)
+}
+
+// mults returns the boost top-speed and thrust multipliers
+func (b BoostTuning) mults() (speed, accel float64) {
+	speed, accel = b.SpeedMul, b.AccelMul
+	if speed <= 0 {
+		speed = PlayerBoostMul
+	}
+	if accel <= 0 {
+		accel = speed
+	}
+	return speed, accel
+}
+
+// spendBoost drains or refills stamina for a step of dt and reports
+// whether boost applies this step
+func (p *Player) spendBoost(dt float64) bool {
+	if p.Boost.Stamina <= 0 {
+		return p.Boosting
+	}
+	if !p.Boosting || p.Stamina <= 0 {
+		if !p.Boosting {
+			p.Stamina = min(p.Boost.Stamina, p.Stamina+BoostRegenRate*dt)
+		}
+		return false
+	}
+	p.Stamina -= dt
+	return true
+}
+
+// streakTier names the kill-streak milestone reached at exactly n kills, or ""
+func streakTier(n int) string {
+	switch n {
+	case 3:
+		return "killing_spree"
+	case 5:
+		return "rampage"
+	case 10:
+		return "unstoppable"
+	}
+	return ""
 }
 
 // TakeDamage reduces HP and returns true if player died
@@ -162,16 +298,95 @@ func (p *Player) TakeDamage(dmg int) bool {
 	if !p.Alive {
 		return false
 	}
+	if p.ShieldT > 0 {
+		dmg = int(math.Round(float64(dmg) * (1 - p.ShieldReduction)))
+	}
+	if p.Overshield > 0 {
+		absorbed := min(float64(dmg), p.Overshield)
+		p.Overshield -= absorbed
+		dmg = int(math.Ceil(float64(dmg) - absorbed))
+	}
 	p.HP -= dmg
 	if p.HP <= 0 {
 		p.HP = 0
 		p.Alive = false
 		p.RespawnT = RespawnTime
+		p.Deaths++
+		p.Streak = 0
 		return true
 	}
 	return false
 }
 
+// Wreck destroys the player outright, ignoring shields (ship-to-ship rams)
+func (p *Player) Wreck() bool {
+	p.ShieldT = 0
+	p.Overshield = 0
+	return p.TakeDamage(p.HP)
+}
+
+// Accuracy returns the fraction of fired shots that hit, in [0, 1]
+func (p *Player) Accuracy() float64 {
+	if p.ShotsFired == 0 {
+		return 0
+	}
+	return float64(p.ShotsHit) / float64(p.ShotsFired)
+}
+
+// RecordHit credits a landed shot and its damage to the shooter
+func (p *Player) RecordHit(dmg int) {
+	p.ShotsHit++
+	p.DamageDealt += dmg
+	p.lifeDamage += dmg
+	p.BestLifeDamage = max(p.BestLifeDamage, p.lifeDamage)
+}
+
+// RecordKill credits a kill made at distance dist (px) at game time now (s)
+func (p *Player) RecordKill(dist, now float64) {
+	if p.Kills > 0 && now-p.lastKillAt <= DoubleKillWindow {
+		if gap := now - p.lastKillAt; p.FastestDouble == 0 || gap < p.FastestDouble {
+			p.FastestDouble = gap
+		}
+	}
+	p.Kills++
+	p.Streak++
+	p.lastKillAt = now
+	p.LongestKill = max(p.LongestKill, dist)
+}
+
+// RecordRam credits a mutual ram as a kill. Rams are point-blank and
+// usually accidental, so they don't feed the LongestKill or FastestDouble
+// highlights.
+func (p *Player) RecordRam() {
+	p.Kills++
+	p.Streak++
+}
+
+// RecordCamFrame samples the player's current position into the kill-cam buffer
+func (p *Player) RecordCamFrame() {
+	p.camFrames[p.camHead] = KillCamFrame{
+		X:    round1(p.X),
+		Y:    round1(p.Y),
+		R:    round2(p.Rotation),
+		Fire: p.camFired,
+	}
+	p.camHead = (p.camHead + 1) % KillCamFrames
+	if p.camLen < KillCamFrames {
+		p.camLen++
+	}
+	p.camFired = false
+}
+
+// KillCam returns the buffered kill-cam frames, oldest first
+func (p *Player) KillCam() []KillCamFrame {
+	frames := make([]KillCamFrame, 0, p.camLen)
+	start := (p.camHead - p.camLen + KillCamFrames) % KillCamFrames
+	for i := 0; i < p.camLen; i++ {
+		frames = append(frames, p.camFrames[(start+i)%KillCamFrames])
+	}
+	return frames
+}
+
 // CanFire returns true if the player can fire a projectile
 func (p *Player) CanFire() bool {
 	return p.Alive && p.Firing && p.FireCD <= 0
@@ -182,45 +397,36 @@ func (p *Player) ToState() PlayerState {
 	vx := round1(p.VX)
 	vy := round1(p.VY)
 	return PlayerState{
-		ID:    p.ID,
-		Name:  p.Name,
-		X:     round1(p.X),
-		Y:     round1(p.Y),
-		R:     round2(p.Rotation),
-		VX:    &vx,
-		VY:    &vy,
-		HP:    p.HP,
-		MaxHP: p.MaxHP,
-		Ship:  p.ShipType,
-		Score: p.Score,
-		Alive: p.Alive,
-		Boost: p.Boosting,
-	}
-}
-
-// randFloat returns a random float64 in [0, 1) using crypto/rand
-// For game use, we use a simple approach
-var randSrc uint64
-
-func randFloat() float64 {
-	// Simple xorshift for non-crypto random
-	randSrc ^= randSrc << 13
-	randSrc ^= randSrc >> 7
-	randSrc ^= randSrc << 17
-	if randSrc == 0 {
-		randSrc = 1
+		ID:         p.ID,
+		Name:       p.Name,
+		X:          round1(p.X),
+		Y:          round1(p.Y),
+		R:          round2(p.Rotation),
+		VX:         &vx,
+		VY:         &vy,
+		HP:         p.HP,
+		MaxHP:      p.MaxHP,
+		Ship:       p.ShipType,
+		Score:      p.Score,
+		Alive:      p.Alive,
+		Boost:      p.boosted,
+		Overshield: int(math.Ceil(p.Overshield)),
+		DC:         p.Disconnected,
+		Wrap:       p.Wrapped,
 	}
-	return float64(randSrc%10000) / 10000.0
 }
 
-func init() {
-	// Seed from crypto/rand
-	b := make([]byte, 8)
-	_, _ = rand.Read(b)
-	for i, v := range b {
-		randSrc |= uint64(v) << (uint(i) * 8)
-	}
-	if randSrc == 0 {
-		randSrc = 1
+// source returns the randomness the player's respawns draw from
+func (p *Player) source() randSource {
+	if p.rng == nil {
+		return globalRand{}
 	}
+	return p.rng
+}
+
+// randFloat returns a random float64 in [0, 1) from the process-wide
+// source. It is safe for concurrent use; sessions draw from their own
+// sources instead, so this is for standalone callers like NewPlayer.
+func randFloat() float64 {
+	return mrand.Float64()
 }
@@ -224,3 +224,108 @@ func TestPlayerToState(t *testing.T) {
 		t.Error("state field mismatch")
 	}
 }
+
+func TestPlayerAccuracyNoShots(t *testing.T) {
+	p := &Player{ID: "test", Alive: true}
+	if p.Accuracy() != 0 {
+		t.Errorf("expected 0 accuracy with no shots, got %f", p.Accuracy())
+	}
+	p.ShotsFired = 2
+	p.RecordHit(15)
+	if p.Accuracy() != 0.5 {
+		t.Errorf("expected 0.5 accuracy, got %f", p.Accuracy())
+	}
+	if p.DamageDealt != 15 {
+		t.Errorf("expected 15 damage dealt, got %d", p.DamageDealt)
+	}
+}
+
+func TestPlayerHighlightRecords(t *testing.T) {
+	p := &Player{ID: "test", Alive: true}
+	p.RecordKill(300, 10)
+	p.RecordKill(800, 12.5)
+	p.RecordKill(100, 20) // outside the double-kill window
+	if p.Kills != 3 || p.LongestKill != 800 {
+		t.Errorf("expected 3 kills, longest 800; got %d, %f", p.Kills, p.LongestKill)
+	}
+	if p.FastestDouble != 2.5 {
+		t.Errorf("expected fastest double 2.5s, got %f", p.FastestDouble)
+	}
+	p.RecordRam()
+	if p.Kills != 4 || p.LongestKill != 800 || p.FastestDouble != 2.5 {
+		t.Errorf("a ram should count as a kill but not a highlight; got %d kills, longest %f, double %f", p.Kills, p.LongestKill, p.FastestDouble)
+	}
+
+	p.RecordHit(40)
+	p.RecordHit(30)
+	p.Respawn()
+	p.RecordHit(50)
+	if p.BestLifeDamage != 70 {
+		t.Errorf("expected best life damage 70, got %d", p.BestLifeDamage)
+	}
+}
+
+func TestPlayerKillCamBounded(t *testing.T) {
+	p := &Player{ID: "test", Alive: true}
+	for i := 0; i < KillCamFrames+5; i++ {
+		p.X = float64(i)
+		p.RecordCamFrame()
+	}
+	frames := p.KillCam()
+	if len(frames) != KillCamFrames {
+		t.Fatalf("expected %d frames, got %d", KillCamFrames, len(frames))
+	}
+	if frames[0].X != 5 || frames[len(frames)-1].X != float64(KillCamFrames+4) {
+		t.Errorf("frames should be the most recent, oldest first; got %f..%f",
+			frames[0].X, frames[len(frames)-1].X)
+	}
+
+	p.Respawn()
+	if len(p.KillCam()) != 0 {
+		t.Error("kill-cam buffer should reset on respawn")
+	}
+}
+
+func TestPlayerBoostTuning(t *testing.T) {
+	topSpeed := func(b BoostTuning) float64 {
+		p := &Player{
+			ID: "boost", X: 100, Y: 100, Alive: true, HP: 100, MaxHP: 100,
+			Boosting: true, Boost: b, Stamina: b.Stamina,
+			TargetX: 1e6, TargetY: 100, SlowThresh: 200,
+		}
+		for i := 0; i < 3*60; i++ {
+			p.TargetX = p.X + 1e6
+			p.Update(1.0 / 60.0)
+		}
+		return math.Hypot(p.VX, p.VY)
+	}
+
+	def := topSpeed(BoostTuning{})
+	high := topSpeed(BoostTuning{SpeedMul: 2.5})
+	if high <= def {
+		t.Errorf("high-boost room should reach a higher boosted speed: %.1f vs %.1f", high, def)
+	}
+	if tired := topSpeed(BoostTuning{Stamina: 1}); tired > PlayerMaxSpeed+1 {
+		t.Errorf("boost should stop once stamina runs out: speed %.1f", tired)
+	}
+}
+
+func TestPlayerTurnAssist(t *testing.T) {
+	turned := func(assist bool, aim float64) float64 {
+		p := &Player{ID: "aim", X: 100, Y: 100, Alive: true, HP: 100, MaxHP: 100, TurnAssist: assist}
+		p.TargetX, p.TargetY = p.X, p.Y // no thrust
+		p.TargetR = aim
+		p.Update(1.0 / 60.0)
+		return p.Rotation
+	}
+
+	if with, without := turned(true, 2.5), turned(false, 2.5); with <= without {
+		t.Errorf("a large aim error should turn faster with assist: %.3f vs %.3f", with, without)
+	}
+	if with, without := turned(true, 0.1), turned(false, 0.1); with != without {
+		t.Errorf("assist should not change fine aiming near the target: %.3f vs %.3f", with, without)
+	}
+	if r := turned(true, TurnAssistAngle+0.01); r > 0.01+TurnSpeed/60.0 {
+		t.Errorf("assist should not overshoot into the fine-aim zone, turned %.3f", r)
+	}
+}
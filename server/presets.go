@@ -0,0 +1,133 @@
+package main
+
+// Match rule presets selectable when creating a session
+const (
+	PresetClassic  = "classic"
+	PresetHardcore = "hardcore"
+	PresetChaos    = "chaos"
+	PresetCustom   = "custom" // classic rules plus the creator's overrides
+)
+
+// presetConfig returns the full config behind a named preset ("" = classic)
+func presetConfig(name string) (MatchConfig, bool) {
+	c := DefaultMatchConfig()
+	switch name {
+	case "", PresetClassic, PresetCustom:
+	case PresetHardcore:
+		// Meaner mobs, scarce healing, a short boost tank and respawns
+		// held for waves, so every death costs something
+		c.MobAggression = 1.75
+		c.PickupHealScale = 0.5
+		c.Boost = BoostTuning{SpeedMul: PlayerBoostMul, AccelMul: PlayerBoostMul, Stamina: 3}
+		c.RespawnWaveInterval = 10
+		c.RamKickCount = 5
+		c.MaxPickups = 2
+		c.PickupSpawnInterval = 40
+	case PresetChaos:
+		// Capital ships everywhere, exploding wrecks, shots that clash
+		// mid-air and a boost that never runs dry
+		c.MobAggression = 2.5
+		c.MobWeights = MobWeights{Tie: 1, StarDestroyer: 1}
+		c.StaticAsteroids = 20
+		c.DeathBlastRadius = 200
+		c.DeathBlastDamage = 40
+		c.ProjectileCancel = true
+		c.OvershieldCap = 50
+		c.PickupMagnetRadius = 300
+		c.Boost = BoostTuning{SpeedMul: 2.5, AccelMul: 2.5}
+		c.MaxMobs = 16
+		c.MobSpawnInterval = 3
+		c.MaxAsteroids = 10
+		c.AsteroidSpawnInterval = 5
+	default:
+		return MatchConfig{}, false
+	}
+	return c, true
+}
+
+// RuleOverrides are the per-field tweaks a creator may layer on a preset.
+// Nil fields keep the preset's value; everything is clamped by Sanitize.
+type RuleOverrides struct {
+	MobAggression       *float64 `json:"mobAggression,omitempty"`
+	PickupHealScale     *float64 `json:"healScale,omitempty"`
+	BoostSpeed          *float64 `json:"boostSpeed,omitempty"`
+	BoostStamina        *float64 `json:"boostStamina,omitempty"`
+	RespawnWaveInterval *float64 `json:"respawnWave,omitempty"`
+	StaticAsteroids     *int     `json:"asteroids,omitempty"`
+	DeathBlastRadius    *float64 `json:"blastRadius,omitempty"`
+	DeathBlastDamage    *int     `json:"blastDamage,omitempty"`
+	ProjectileCancel    *bool    `json:"projectileCancel,omitempty"`
+	TurnAssist          *bool    `json:"turnAssist,omitempty"`
+
+	// Arena density: spawn intervals in seconds and live caps
+	MobSpawnInterval      *float64 `json:"mobSpawn,omitempty"`
+	MaxMobs               *int     `json:"maxMobs,omitempty"`
+	AsteroidSpawnInterval *float64 `json:"asteroidSpawn,omitempty"`
+	MaxAsteroids          *int     `json:"maxAsteroids,omitempty"`
+	PickupSpawnInterval   *float64 `json:"pickupSpawn,omitempty"`
+	MaxPickups            *int     `json:"maxPickups,omitempty"`
+}
+
+// apply copies the set overrides onto c
+func (o RuleOverrides) apply(c *MatchConfig) {
+	if o.MobAggression != nil {
+		c.MobAggression = *o.MobAggression
+	}
+	if o.PickupHealScale != nil {
+		c.PickupHealScale = *o.PickupHealScale
+	}
+	if o.BoostSpeed != nil {
+		// Thrust follows speed, as with the classic boost
+		c.Boost.SpeedMul, c.Boost.AccelMul = *o.BoostSpeed, *o.BoostSpeed
+	}
+	if o.BoostStamina != nil {
+		c.Boost.Stamina = *o.BoostStamina
+	}
+	if o.RespawnWaveInterval != nil {
+		c.RespawnWaveInterval = *o.RespawnWaveInterval
+	}
+	if o.StaticAsteroids != nil {
+		c.StaticAsteroids = *o.StaticAsteroids
+	}
+	if o.DeathBlastRadius != nil {
+		c.DeathBlastRadius = *o.DeathBlastRadius
+	}
+	if o.DeathBlastDamage != nil {
+		c.DeathBlastDamage = *o.DeathBlastDamage
+	}
+	if o.ProjectileCancel != nil {
+		c.ProjectileCancel = *o.ProjectileCancel
+	}
+	if o.TurnAssist != nil {
+		c.TurnAssist = *o.TurnAssist
+	}
+	if o.MobSpawnInterval != nil {
+		c.MobSpawnInterval = *o.MobSpawnInterval
+	}
+	if o.MaxMobs != nil {
+		c.MaxMobs = *o.MaxMobs
+	}
+	if o.AsteroidSpawnInterval != nil {
+		c.AsteroidSpawnInterval = *o.AsteroidSpawnInterval
+	}
+	if o.MaxAsteroids != nil {
+		c.MaxAsteroids = *o.MaxAsteroids
+	}
+	if o.PickupSpawnInterval != nil {
+		c.PickupSpawnInterval = *o.PickupSpawnInterval
+	}
+	if o.MaxPickups != nil {
+		c.MaxPickups = *o.MaxPickups
+	}
+}
+
+// ResolvePreset builds a session's MatchConfig from a preset name and the
+// creator's overrides. Out-of-range overrides are clamped, not rejected.
+func ResolvePreset(name string, o RuleOverrides) (MatchConfig, error) {
+	c, ok := presetConfig(name)
+	if !ok {
+		return MatchConfig{}, ErrUnknownPreset
+	}
+	o.apply(&c)
+	return c.Sanitize(), nil
+}
@@ -20,14 +20,34 @@ type Projectile struct {
 	Life     float64
 	Damage   int
 	Alive    bool
+	Wrapped  bool // crossed a world edge since the last state broadcast
+	velSent  bool // current velocity went out in a broadcast (late viewers: see sentView)
+
+	// Position before the last Update, for swept collision of fast shots
+	PrevX, PrevY float64
+	HasPrev      bool
 }
 
 // NewProjectile creates a projectile from a player's position and facing direction
 func NewProjectile(owner *Player) *Projectile {
+	p := &Projectile{}
+	p.resetFromPlayer(owner)
+	return p
+}
+
+// NewMobProjectile creates a projectile from a mob's position and facing direction
+func NewMobProjectile(mob *Mob) *Projectile {
+	p := &Projectile{}
+	p.resetFromMob(mob)
+	return p
+}
+
+// resetFromPlayer overwrites every field so p is a fresh shot fired by owner
+func (p *Projectile) resetFromPlayer(owner *Player) {
 	id := GenerateID(3)
 	vx := math.Cos(owner.Rotation) * ProjectileSpeed
 	vy := math.Sin(owner.Rotation) * ProjectileSpeed
-	return &Projectile{
+	*p = Projectile{
 		ID:       id,
 		OwnerID:  owner.ID,
 		X:        owner.X + math.Cos(owner.Rotation)*ProjectileOffset,
@@ -41,12 +61,12 @@ func NewProjectile(owner *Player) *Projectile {
 	}
 }
 
-// NewMobProjectile creates a projectile from a mob's position and facing direction
-func NewMobProjectile(mob *Mob) *Projectile {
+// resetFromMob overwrites every field so p is a fresh shot fired by mob
+func (p *Projectile) resetFromMob(mob *Mob) {
 	id := GenerateID(3)
 	vx := math.Cos(mob.Rotation) * ProjectileSpeed
 	vy := math.Sin(mob.Rotation) * ProjectileSpeed
-	return &Projectile{
+	*p = Projectile{
 		ID:       id,
 		OwnerID:  mob.ID,
 		X:        mob.X + math.Cos(mob.Rotation)*mob.ProjOffset,
@@ -60,25 +80,46 @@ func NewMobProjectile(mob *Mob) *Projectile {
 	}
 }
 
+// Reflect turns the shot back along its path and hands it to by, who then
+// gets credit for whatever it hits. The new owner is immune to it and the
+// old one is not. Its swept path restarts here so it can't hit back through
+// the reflector.
+func (p *Projectile) Reflect(by *Player) {
+	p.VX, p.VY = -p.VX, -p.VY
+	p.Rotation = NormalizeAngle(p.Rotation + math.Pi)
+	p.OwnerID = by.ID
+	p.HasPrev = false
+	p.velSent = false // clients must learn the new heading
+}
+
 // Update moves the projectile one tick
 func (p *Projectile) Update(dt float64) {
 	if !p.Alive {
 		return
 	}
+	p.PrevX, p.PrevY, p.HasPrev = p.X, p.Y, true
 	p.X += p.VX * dt
 	p.Y += p.VY * dt
 	p.Life -= dt
 
-	// Wrap around world
+	// Wrap around world (shift the previous position too so the swept path stays continuous)
 	if p.X < 0 {
 		p.X += WorldWidth
+		p.PrevX += WorldWidth
+		p.Wrapped = true
 	} else if p.X > WorldWidth {
 		p.X -= WorldWidth
+		p.PrevX -= WorldWidth
+		p.Wrapped = true
 	}
 	if p.Y < 0 {
 		p.Y += WorldHeight
+		p.PrevY += WorldHeight
+		p.Wrapped = true
 	} else if p.Y > WorldHeight {
 		p.Y -= WorldHeight
+		p.PrevY -= WorldHeight
+		p.Wrapped = true
 	}
 
 	if p.Life <= 0 {
@@ -86,13 +127,42 @@ func (p *Projectile) Update(dt float64) {
 	}
 }
 
+// swept returns true if this tick's travel is long enough to tunnel through a target of radius r
+func (p *Projectile) swept(r float64) bool {
+	return p.HasPrev && DistanceSq(p.PrevX, p.PrevY, p.X, p.Y) > r*r
+}
+
+// HitsCircle checks the projectile against a circle, sweeping along this
+// tick's path when the shot moved farther than the target's radius
+func (p *Projectile) HitsCircle(cx, cy, r float64) bool {
+	if p.swept(r) {
+		return SegmentCircleIntersect(p.PrevX, p.PrevY, p.X, p.Y, cx, cy, r+ProjectileRadius)
+	}
+	return CheckCollision(p.X, p.Y, ProjectileRadius, cx, cy, r)
+}
+
+// QueryArea returns a broad-phase query circle covering this tick's whole
+// path against targets up to radius r
+func (p *Projectile) QueryArea(r float64) (x, y, radius float64) {
+	if p.HasPrev {
+		half := Distance(p.PrevX, p.PrevY, p.X, p.Y) / 2
+		return (p.PrevX + p.X) / 2, (p.PrevY + p.Y) / 2, half + ProjectileRadius + r
+	}
+	return p.X, p.Y, ProjectileRadius + r
+}
+
 // ToState converts to protocol state
 func (p *Projectile) ToState() ProjectileState {
+	vx := round1(p.VX)
+	vy := round1(p.VY)
 	return ProjectileState{
 		ID:    p.ID,
 		X:     round1(p.X),
 		Y:     round1(p.Y),
 		R:     round1(p.Rotation),
 		Owner: p.OwnerID,
+		VX:    &vx,
+		VY:    &vy,
+		Wrap:  p.Wrapped,
 	}
 }
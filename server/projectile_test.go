@@ -101,3 +101,171 @@ func TestProjectileToState(t *testing.T) {
 		t.Error("state mismatch")
 	}
 }
+
+func TestPooledProjectileMatchesFresh(t *testing.T) {
+	owner := &Player{ID: "owner1", X: 500, Y: 500, Rotation: 1.2, VX: 40, VY: -20}
+	fresh := NewProjectile(owner)
+
+	g := NewGame()
+	pooled := g.acquireProjectile()
+	pooled.resetFromPlayer(owner)
+
+	// IDs are random; everything else must match
+	pooled.ID = fresh.ID
+	if *pooled != *fresh {
+		t.Errorf("pooled projectile %+v differs from fresh %+v", *pooled, *fresh)
+	}
+}
+
+func TestReusedProjectileFullyReset(t *testing.T) {
+	g := NewGame()
+	mob := NewStarDestroyerMob()
+	mob.X, mob.Y = 1000, 1000
+
+	proj := g.acquireProjectile()
+	proj.resetFromMob(mob)
+	oldID := proj.ID
+	// Simulate a projectile that flew, hit something and despawned
+	proj.Update(0.5)
+	proj.Alive = false
+	proj.Life = -1
+	g.releaseProjectile(proj)
+
+	owner := &Player{ID: "owner1", X: 200, Y: 300}
+	reused := g.acquireProjectile()
+	if reused != proj {
+		t.Fatal("expected the released projectile to be reused")
+	}
+	reused.resetFromPlayer(owner)
+	if reused.ID == oldID {
+		t.Error("reused projectile should get a new ID")
+	}
+	if !reused.Alive || reused.Life != ProjectileLifetime {
+		t.Error("reused projectile should be alive with a full lifetime")
+	}
+	if reused.OwnerID != owner.ID || reused.Damage != ProjectileDamage {
+		t.Errorf("reused projectile kept stale owner/damage: %s/%d", reused.OwnerID, reused.Damage)
+	}
+	if reused.X != owner.X+ProjectileOffset || reused.Y != owner.Y {
+		t.Errorf("reused projectile at (%f,%f), expected spawn ahead of owner", reused.X, reused.Y)
+	}
+}
+
+// projSink keeps benchmarked projectiles on the heap, as they are in a live game
+var projSink *Projectile
+
+func BenchmarkProjectileAlloc(b *testing.B) {
+	owner := &Player{ID: "owner1", X: 500, Y: 500}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		projSink = NewProjectile(owner)
+	}
+}
+
+func BenchmarkProjectilePooled(b *testing.B) {
+	owner := &Player{ID: "owner1", X: 500, Y: 500}
+	g := NewGame()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		proj := g.acquireProjectile()
+		proj.resetFromPlayer(owner)
+		projSink = proj
+		g.releaseProjectile(proj)
+	}
+}
+
+func TestSegmentCircleIntersect(t *testing.T) {
+	if !SegmentCircleIntersect(0, 0, 100, 0, 50, 10, 15) {
+		t.Error("segment passing 10px from center should hit radius 15")
+	}
+	if SegmentCircleIntersect(0, 0, 100, 0, 50, 20, 15) {
+		t.Error("segment passing 20px from center should miss radius 15")
+	}
+	if SegmentCircleIntersect(0, 0, 100, 0, 130, 0, 15) {
+		t.Error("circle beyond the segment end should miss")
+	}
+	if !SegmentCircleIntersect(5, 5, 5, 5, 10, 5, 6) {
+		t.Error("zero-length segment should fall back to a point test")
+	}
+}
+
+func TestFastProjectileDoesNotTunnel(t *testing.T) {
+	g := NewGame()
+	target := g.AddPlayer("Target")
+	target.X, target.Y = 1050, 1000
+
+	// Moves 100px per tick: from 1000 straight past the target to 1100
+	proj := &Projectile{
+		ID: "fast", OwnerID: "someone", X: 1000, Y: 1000,
+		VX: 100 * TickRate, Life: ProjectileLifetime, Damage: ProjectileDamage, Alive: true,
+	}
+	g.projectiles = append(g.projectiles, proj)
+	g.update()
+
+	if proj.Alive {
+		t.Fatalf("fast projectile should have hit the target it passed through (now at %f)", proj.X)
+	}
+	if target.HP != PlayerMaxHP-ProjectileDamage {
+		t.Errorf("expected target HP %d, got %d", PlayerMaxHP-ProjectileDamage, target.HP)
+	}
+}
+
+func TestFastProjectileDoesNotTunnelAsteroid(t *testing.T) {
+	g := NewGame()
+	ast := &Asteroid{ID: "rock", X: 1100, Y: 1000, Alive: true}
+	g.asteroids[ast.ID] = ast
+
+	// Moves 200px per tick: from 1000 straight through the asteroid to 1200
+	proj := &Projectile{
+		ID: "fast", OwnerID: "someone", X: 1000, Y: 1000,
+		VX: 200 * TickRate, Life: ProjectileLifetime, Damage: ProjectileDamage, Alive: true,
+	}
+	g.projectiles = append(g.projectiles, proj)
+	g.update()
+
+	if proj.Alive {
+		t.Fatalf("fast projectile should have stopped at the asteroid it passed through (now at %f)", proj.X)
+	}
+}
+
+func TestProjectileSweepAcrossWrap(t *testing.T) {
+	proj := &Projectile{X: WorldWidth - 5, Y: 100, VX: 600, Life: 1, Alive: true}
+	proj.Update(1.0 / 60.0)
+	if proj.X > 50 {
+		t.Fatalf("projectile should have wrapped, X=%f", proj.X)
+	}
+	// The swept path must stay short, not span the whole world
+	if d := Distance(proj.PrevX, proj.PrevY, proj.X, proj.Y); d > 20 {
+		t.Errorf("swept path across the wrap should be ~10px, got %f", d)
+	}
+}
+
+func TestReflectedShotCreditsReflector(t *testing.T) {
+	g := NewGame()
+	shooter := g.AddPlayer("Shooter")
+	reflector := g.AddPlayer("Reflector")
+	shooter.X, shooter.Y = 1000, 1000
+	reflector.X, reflector.Y = 1300, 1000
+	shooter.HP = ProjectileDamage
+
+	proj := NewProjectile(shooter)
+	proj.X, proj.Y = reflector.X-PlayerRadius-10, reflector.Y
+	proj.Reflect(reflector)
+	if proj.OwnerID != reflector.ID || proj.VX >= 0 {
+		t.Fatalf("reflected shot should belong to the reflector and head back, owner=%s vx=%.0f", proj.OwnerID, proj.VX)
+	}
+
+	// Fly it back into the original shooter
+	proj.X = shooter.X + PlayerRadius + 5
+	proj.Update(1.0 / 60.0)
+	g.projectiles = append(g.projectiles, proj)
+	g.buildSpatialGrid()
+	g.checkCollisions()
+
+	if shooter.Alive {
+		t.Fatal("reflected shot should hit its original shooter")
+	}
+	if reflector.Kills != 1 || shooter.KilledBy != reflector.ID {
+		t.Errorf("kill should be credited to the reflector, kills=%d killedBy=%q", reflector.Kills, shooter.KilledBy)
+	}
+}
@@ -6,13 +6,25 @@ import (
 
 // Client -> Server message types
 const (
-	MsgJoin    = "join"
-	MsgLeave   = "leave"
-	MsgInput   = "input"
-	MsgCreate  = "create"  // create session
-	MsgList    = "list"    // list sessions
-	MsgCheck   = "check"   // check if session exists
-	MsgControl = "control" // phone controller attach
+	MsgJoin        = "join"
+	MsgLeave       = "leave"
+	MsgInput       = "input"
+	MsgCreate      = "create"       // create session
+	MsgList        = "list"         // list sessions
+	MsgCheck       = "check"        // check if session exists
+	MsgControl     = "control"      // phone controller attach
+	MsgQuality     = "quality"      // per-connection update quality options
+	MsgDebug       = "debug"        // toggle the developer debug feed
+	MsgSeed        = "seed"         // host sets the PvE spawn seed
+	MsgPause       = "pause"        // host freezes the match
+	MsgResume      = "resume"       // host resumes the match
+	MsgChat        = "chat"         // player chat line
+	MsgSpectate    = "spectate"     // watch a session without playing
+	MsgRejoin      = "rejoin"       // reclaim a ship after a dropped connection
+	MsgViewport    = "viewport"     // client screen size, for viewport culling
+	MsgEnableDelta = "enable_delta" // switch to keyframe + delta state frames
+	MsgAddBot      = "add_bot"      // host adds an AI pilot
+	MsgRemoveBot   = "remove_bot"   // host removes an AI pilot
 )
 
 // Server -> Client message types
@@ -31,6 +43,17 @@ const (
 	MsgCtrlOff    = "ctrl_off"    // notify desktop: controller detached
 	MsgHit        = "hit"         // damage dealt to an entity
 	MsgMobSay     = "mob_say"     // mob speech bubble
+	MsgPaused     = "paused"      // match paused or resumed
+	MsgDebugState = "debug_state" // server internals for developer overlays
+	MsgSeedSet    = "seed_set"    // host changed the spawn seed
+	MsgObstacles  = "obstacles"   // static asteroid field (sent on join and when it changes)
+	MsgChatMsg    = "chat_msg"    // chat line relayed to the session
+	MsgSpectating = "spectating"  // spectator attach confirmed
+	MsgScoreboard = "scoreboard"  // live roster stats at a throttled rate
+	MsgTeleport   = "teleport"    // a ship jumped; snap rather than interpolate
+	MsgStreak     = "streak"      // a player reached a kill-streak milestone
+	MsgSpark      = "spark"       // two shots cancelled each other out
+	MsgKicked     = "kicked"      // player was removed from the session
 )
 
 // Envelope wraps all outgoing messages with a type field
@@ -64,32 +87,70 @@ type JoinMsg struct {
 type CreateMsg struct {
 	Name        string `json:"name"`
 	SessionName string `json:"sname"`
+	// CloseWhenEmpty removes the session as soon as the host or the last
+	// player leaves
+	CloseWhenEmpty bool `json:"ephemeral,omitempty"`
+	// Seed fixes the PvE spawn seed (0 = random)
+	Seed int64 `json:"seed,omitempty"`
+	// MobWeights sets the mob archetype mix, e.g. a swarm or boss-rush room
+	MobWeights MobWeights `json:"mobs"`
+	// Preset picks the match rules (classic, hardcore, chaos or custom),
+	// with Rules overriding individual settings
+	Preset string        `json:"preset,omitempty"`
+	Rules  RuleOverrides `json:"rules,omitempty"`
+}
+
+// ObstacleState is a static asteroid; obstacles never move, so they are
+// sent once instead of in every GameState
+type ObstacleState struct {
+	ID string  `json:"id"`
+	X  float64 `json:"x"`
+	Y  float64 `json:"y"`
+	R  float64 `json:"r"`
+}
+
+// ObstaclesMsg lists a session's static asteroids
+type ObstaclesMsg struct {
+	List []ObstacleState `json:"list"`
+}
+
+// SeedMsg carries a session's spawn seed (client request and broadcast)
+type SeedMsg struct {
+	Seed int64 `json:"seed"`
 }
 
 // PlayerState is broadcast per player each tick
 type PlayerState struct {
-	ID   string  `json:"id" msgpack:"id"`
-	Name string  `json:"n" msgpack:"n"`
-	X    float64 `json:"x" msgpack:"x"`
-	Y    float64 `json:"y" msgpack:"y"`
-	R    float64 `json:"r" msgpack:"r"`
-	VX   *float64 `json:"vx,omitempty" msgpack:"vx,omitempty"`
-	VY   *float64 `json:"vy,omitempty" msgpack:"vy,omitempty"`
-	HP   int     `json:"hp" msgpack:"hp"`
-	MaxHP int    `json:"mhp" msgpack:"mhp"`
-	Ship int     `json:"s" msgpack:"s"`
-	Score int    `json:"sc" msgpack:"sc"`
-	Alive bool   `json:"a" msgpack:"a"`
-	Boost bool   `json:"b,omitempty" msgpack:"b,omitempty"`
+	ID         string   `json:"id" msgpack:"id"`
+	Name       string   `json:"n" msgpack:"n"`
+	X          float64  `json:"x" msgpack:"x"`
+	Y          float64  `json:"y" msgpack:"y"`
+	R          float64  `json:"r" msgpack:"r"`
+	VX         *float64 `json:"vx,omitempty" msgpack:"vx,omitempty"`
+	VY         *float64 `json:"vy,omitempty" msgpack:"vy,omitempty"`
+	HP         int      `json:"hp" msgpack:"hp"`
+	MaxHP      int      `json:"mhp" msgpack:"mhp"`
+	Ship       int      `json:"s" msgpack:"s"`
+	Score      int      `json:"sc" msgpack:"sc"`
+	Alive      bool     `json:"a" msgpack:"a"`
+	Boost      bool     `json:"b,omitempty" msgpack:"b,omitempty"`
+	Overshield int      `json:"os,omitempty" msgpack:"os,omitempty"`
+	DC         bool     `json:"dc,omitempty" msgpack:"dc,omitempty"` // client dropped, may rejoin
+	Wrap       bool     `json:"w,omitempty" msgpack:"w,omitempty"`   // wrapped an edge: snap, don't interpolate
 }
 
 // ProjectileState is broadcast per projectile
 type ProjectileState struct {
-	ID string  `json:"id" msgpack:"id"`
-	X  float64 `json:"x" msgpack:"x"`
-	Y  float64 `json:"y" msgpack:"y"`
-	R  float64 `json:"r" msgpack:"r"`
-	Owner string `json:"o" msgpack:"o"`
+	ID    string  `json:"id" msgpack:"id"`
+	X     float64 `json:"x" msgpack:"x"`
+	Y     float64 `json:"y" msgpack:"y"`
+	R     float64 `json:"r" msgpack:"r"`
+	Owner string  `json:"o" msgpack:"o"`
+	// VX/VY are sent in the first frame after the shot is fired (every
+	// frame with MatchConfig.DisableVelocityDelta); shots fly straight
+	VX   *float64 `json:"vx,omitempty" msgpack:"vx,omitempty"`
+	VY   *float64 `json:"vy,omitempty" msgpack:"vy,omitempty"`
+	Wrap bool     `json:"w,omitempty" msgpack:"w,omitempty"` // wrapped an edge: snap, don't interpolate
 }
 
 // MobState is broadcast per mob
@@ -104,6 +165,7 @@ type MobState struct {
 	MaxHP int      `json:"mhp" msgpack:"mhp"`
 	Ship  int      `json:"s" msgpack:"s"`
 	Alive bool     `json:"a" msgpack:"a"`
+	Wrap  bool     `json:"w,omitempty" msgpack:"w,omitempty"` // wrapped an edge: snap, don't interpolate
 }
 
 // AsteroidState is broadcast per asteroid
@@ -129,18 +191,43 @@ type GameState struct {
 	Asteroids   []AsteroidState   `json:"a" msgpack:"a"`
 	Pickups     []PickupState     `json:"pk" msgpack:"pk"`
 	Tick        uint64            `json:"tick" msgpack:"tick"`
+	// Tiers is set for clients using tiered updates: a bitmask of the distance
+	// tiers included in this frame. Entities in omitted tiers are unchanged,
+	// not removed.
+	Tiers uint8 `json:"tr,omitempty" msgpack:"tr,omitempty"`
+	// Paused is set while the host has frozen the match
+	Paused bool `json:"pa,omitempty" msgpack:"pa,omitempty"`
+	// Following is the player whose view a dead recipient is watching
+	Following string `json:"fw,omitempty" msgpack:"fw,omitempty"`
+	// Key and Removed are set for clients in delta mode (MsgEnableDelta).
+	// A keyframe carries every entity in view; other frames carry only
+	// entities that changed, and Removed lists IDs that left the frame.
+	Key     bool     `json:"k,omitempty" msgpack:"k,omitempty"`
+	Removed []string `json:"rm,omitempty" msgpack:"rm,omitempty"`
 }
 
 // WelcomeMsg is sent to a player when they join
 type WelcomeMsg struct {
 	ID   string `json:"id"`
 	Ship int    `json:"s"`
+	Host bool   `json:"host,omitempty"` // may pause/resume the match
+	// Token lets the client reclaim this ship with MsgRejoin after a drop
+	Token string `json:"token,omitempty"`
 }
 
 // DeathMsg notifies a player they died
 type DeathMsg struct {
-	KillerID   string `json:"kid"`
-	KillerName string `json:"kn"`
+	KillerID   string         `json:"kid"`
+	KillerName string         `json:"kn"`
+	Cam        []KillCamFrame `json:"cam,omitempty"` // killer's recent positions, oldest first
+}
+
+// KillCamFrame is one sample of the killer's recent movement
+type KillCamFrame struct {
+	X    float64 `json:"x"`
+	Y    float64 `json:"y"`
+	R    float64 `json:"r"`
+	Fire bool    `json:"f,omitempty"`
 }
 
 // KillMsg is broadcast to all players in session
@@ -149,13 +236,67 @@ type KillMsg struct {
 	KillerName string `json:"kn"`
 	VictimID   string `json:"vid"`
 	VictimName string `json:"vn"`
+	Dist       int    `json:"dist,omitempty"` // shooter-victim distance at the killing hit (px)
+}
+
+// StreakMsg announces a kill-streak milestone
+type StreakMsg struct {
+	PlayerID string `json:"pid"`
+	Name     string `json:"name"`
+	Streak   int    `json:"n"`
+	Tier     string `json:"tier"` // "killing_spree" (3), "rampage" (5), "unstoppable" (10)
+}
+
+// TeleportMsg gives a ship's new position after a discontinuous move
+type TeleportMsg struct {
+	ID string  `json:"id"`
+	X  float64 `json:"x"`
+	Y  float64 `json:"y"`
+}
+
+// ScoreEntry is one player's row on the live scoreboard
+type ScoreEntry struct {
+	ID     string `json:"id"`
+	Name   string `json:"n"`
+	Score  int    `json:"sc"`
+	Kills  int    `json:"k"`
+	Deaths int    `json:"d"`
+
+	// Shooting stats (see Player.Accuracy)
+	ShotsFired  int     `json:"sf"`
+	ShotsHit    int     `json:"sh"`
+	DamageDealt int     `json:"dmg"`
+	Accuracy    float64 `json:"acc"` // fraction of shots that hit, in [0, 1]
+}
+
+// ScoreboardMsg is the live roster in rank order, with the session's
+// highlight awards
+type ScoreboardMsg struct {
+	Players    []ScoreEntry `json:"p"`
+	Highlights []Highlight  `json:"hl,omitempty"`
+}
+
+// Highlight is a fun per-session record, e.g. the longest-range kill
+type Highlight struct {
+	Award    string  `json:"award"` // "longest_kill", "most_life_damage", "fastest_double"
+	PlayerID string  `json:"pid"`
+	Name     string  `json:"name"`
+	Value    float64 `json:"v"`
 }
 
 // SessionInfo is used in the session list
 type SessionInfo struct {
-	ID      string `json:"id"`
-	Name    string `json:"name"`
-	Players int    `json:"players"`
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Players    int    `json:"players"`
+	Seed       int64  `json:"seed"`
+	Spectators int    `json:"spectators"`
+}
+
+// SessionDebug is a session list entry with tick timings, for /api/debug
+type SessionDebug struct {
+	SessionInfo
+	Tick TickStats `json:"tick"`
 }
 
 // ErrorMsg sends error to client
@@ -163,12 +304,81 @@ type ErrorMsg struct {
 	Msg string `json:"msg"`
 }
 
+// RejoinMsg is sent by a reconnecting client to reclaim its ship
+type RejoinMsg struct {
+	SID   string `json:"sid"`
+	Token string `json:"token"`
+}
+
+// SpectateMsg is sent by a client that wants to watch a session
+type SpectateMsg struct {
+	SID string `json:"sid"`
+}
+
 // ControlMsg is sent by a phone controller to attach to a player
 type ControlMsg struct {
 	SID      string `json:"sid"`
 	PlayerID string `json:"pid"`
 }
 
+// PausedMsg is broadcast when the host pauses or resumes the match
+type PausedMsg struct {
+	Paused bool   `json:"paused"`
+	By     string `json:"by"` // host's name
+}
+
+// ChatSendMsg is a chat line sent by a player
+type ChatSendMsg struct {
+	Text string `json:"text"`
+}
+
+// ChatBroadcastMsg relays a chat line to everyone in the session
+type ChatBroadcastMsg struct {
+	PlayerID string `json:"pid"`
+	Name     string `json:"name"`
+	Text     string `json:"text"`
+}
+
+// DebugMsg toggles the developer debug feed for a connection
+type DebugMsg struct {
+	On bool `json:"on"`
+}
+
+// DebugCell is the occupancy of one spatial grid cell
+type DebugCell struct {
+	Cell int `json:"c"` // row-major cell index
+	N    int `json:"n"` // entity refs in the cell
+}
+
+// DebugStateMsg exposes server internals to developer clients. It is only
+// sent to connections that enabled the feed on a server started with it.
+type DebugStateMsg struct {
+	Tick     uint64      `json:"tick"`
+	CellSize float64     `json:"cs"`
+	Cols     int         `json:"cols"`
+	Cells    []DebugCell `json:"cells"` // non-empty cells only
+	// Checks counts broad-phase candidates examined per entity since the last frame
+	Checks map[string]int `json:"checks"`
+	// MobTargets maps mob ID to the tracked player ID ("" when wandering)
+	MobTargets map[string]string `json:"targets"`
+}
+
+// QualityMsg is sent by a client to tune how it receives state updates
+type QualityMsg struct {
+	Tiered bool `json:"tiered"` // update far entities less often
+}
+
+// ViewportMsg reports the client's visible area in world pixels
+type ViewportMsg struct {
+	W float64 `json:"w"`
+	H float64 `json:"h"`
+}
+
+// EnableDeltaMsg turns delta state frames on or off for the connection
+type EnableDeltaMsg struct {
+	On bool `json:"on"`
+}
+
 // CheckMsg is sent by client to check if a session exists
 type CheckMsg struct {
 	SID string `json:"sid"`
@@ -191,6 +401,17 @@ type HitMsg struct {
 	AttackerID string  `json:"aid"`
 }
 
+// KickedMsg tells a client why its player was removed
+type KickedMsg struct {
+	Reason string `json:"reason"` // "idle" or "ramming"
+}
+
+// SparkMsg marks where two projectiles cancelled out
+type SparkMsg struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
 // MobSayMsg is broadcast when a mob says a phrase
 type MobSayMsg struct {
 	MobID string `json:"mid"`
@@ -0,0 +1,82 @@
+package main
+
+import "time"
+
+// DefaultRejoinGrace is how long a dropped player's ship stays in the
+// session waiting for the client to reconnect with its rejoin token
+// (see SessionManager.rejoinGrace)
+const DefaultRejoinGrace = 30 * time.Second
+
+// DisconnectPlayer keeps a dropped player's ship in the world with its input
+// frozen. Returns the disconnect sequence for RemoveIfDisconnected, or 0 if
+// the player isn't in the game.
+func (g *Game) DisconnectPlayer(id string) uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	p, ok := g.players[id]
+	if !ok {
+		return 0
+	}
+	delete(g.clients, id)
+	p.Disconnected = true
+	p.Firing = false
+	p.Boosting = false
+	p.TargetX, p.TargetY = p.X, p.Y // pointer on the ship: brake to a stop
+	g.disconnectSeq++
+	p.disconnectSeq = g.disconnectSeq
+	return p.disconnectSeq
+}
+
+// RemoveIfDisconnected removes the player if they are still disconnected
+// since the drop numbered seq. Reports whether they were removed.
+func (g *Game) RemoveIfDisconnected(id string, seq uint64) bool {
+	g.mu.RLock()
+	p, ok := g.players[id]
+	still := ok && p.Disconnected && p.disconnectSeq == seq
+	g.mu.RUnlock()
+	if still {
+		g.RemovePlayer(id)
+	}
+	return still
+}
+
+// Rejoin rebinds a disconnected player to client by rejoin token.
+// Returns nil if no disconnected player holds the token.
+func (g *Game) Rejoin(token string, client Broadcaster) *Player {
+	if token == "" {
+		return nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, p := range g.players {
+		if p.Disconnected && p.RejoinToken == token {
+			p.Disconnected = false
+			p.delta = nil // the new connection starts on full frames
+			p.sent.reset()
+			g.clients[p.ID] = client
+			if len(g.obstacles) > 0 {
+				client.SendJSON(g.obstaclesMsg())
+			}
+			return p
+		}
+	}
+	return nil
+}
+
+// DisconnectPlayer holds a dropped player's ship for the rejoin grace, then
+// removes it if the client hasn't come back
+func (sm *SessionManager) DisconnectPlayer(sessionID, playerID string) {
+	sess := sm.GetSession(sessionID)
+	if sess == nil {
+		return
+	}
+	seq := sess.Game.DisconnectPlayer(playerID)
+	if seq == 0 {
+		return
+	}
+	time.AfterFunc(sm.rejoinGrace, func() {
+		if sess.Game.RemoveIfDisconnected(playerID, seq) {
+			sm.afterRemove(sess)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package main
+
+import "sort"
+
+const (
+	DefaultScoreboardInterval = 0.5 // seconds between MsgScoreboard updates (2Hz)
+	MinScoreboardInterval     = 0.1
+	MaxScoreboardInterval     = 10.0
+)
+
+// scoreboardEvery returns the ticks between scoreboard updates
+func (g *Game) scoreboardEvery() uint64 {
+	return uint64(max(1, int(g.config.ScoreboardInterval*TickRate+0.5)))
+}
+
+// maybeBroadcastScoreboard sends MsgScoreboard when an update is due
+func (g *Game) maybeBroadcastScoreboard() {
+	if g.catchingUp || g.tick-g.lastScoreboard < g.scoreboardEvery() {
+		return
+	}
+	g.lastScoreboard = g.tick
+	g.broadcastMsg(Envelope{T: MsgScoreboard, Data: g.scoreboard()})
+}
+
+// scoreboard builds the live roster in rank order (see rankedBefore)
+func (g *Game) scoreboard() ScoreboardMsg {
+	rows := make([]ScoreEntry, 0, len(g.players))
+	for _, p := range g.players {
+		rows = append(rows, ScoreEntry{
+			ID:          p.ID,
+			Name:        p.Name,
+			Score:       p.Score,
+			Kills:       p.Kills,
+			Deaths:      p.Deaths,
+			ShotsFired:  p.ShotsFired,
+			ShotsHit:    p.ShotsHit,
+			DamageDealt: p.DamageDealt,
+			Accuracy:    round2(p.Accuracy()),
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rankedBefore(rows[i], rows[j]) })
+	return ScoreboardMsg{Players: rows, Highlights: g.highlights()}
+}
+
+// rankedBefore orders scoreboard rows: highest score, then most kills,
+// then fewest deaths, with the player ID as a final stable tiebreak
+func rankedBefore(a, b ScoreEntry) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	if a.Kills != b.Kills {
+		return a.Kills > b.Kills
+	}
+	if a.Deaths != b.Deaths {
+		return a.Deaths < b.Deaths
+	}
+	return a.ID < b.ID
+}
@@ -76,7 +76,7 @@ func SetupRoutes(hub *Hub, clientRustDir string) *http.ServeMux {
 
 	// Debug endpoint
 	mux.HandleFunc("/api/debug", func(w http.ResponseWriter, r *http.Request) {
-		sessions := hub.sessions.ListSessions()
+		sessions := hub.sessions.DebugSessions()
 		var memStats runtime.MemStats
 		runtime.ReadMemStats(&memStats)
 		info := map[string]interface{}{
@@ -85,6 +85,8 @@ func SetupRoutes(hub *Hub, clientRustDir string) *http.ServeMux {
 			"sessions":    sessions,
 			"heap_mb":     float64(memStats.HeapAlloc) / 1024 / 1024,
 			"total_conns": hub.TotalConns(),
+			"entities":    hub.entities.Total(),
+			"entity_cap":  hub.entities.Limit(),
 		}
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(info)
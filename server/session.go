@@ -7,7 +7,9 @@ import (
 
 const maxSessions = 100
 
-var SessionIdleTimeout = time.Minute
+// DefaultSessionIdleTimeout is how long an empty session lingers before it
+// is closed (see SessionManager.idleTimeout)
+const DefaultSessionIdleTimeout = time.Minute
 
 // Session represents a game session that players can join
 type Session struct {
@@ -15,6 +17,11 @@ type Session struct {
 	Name string
 	Game *Game
 
+	// CloseWhenEmpty tears the session down as soon as the host or the last
+	// player leaves instead of waiting out the idle timeout (ephemeral
+	// private rooms)
+	CloseWhenEmpty bool
+
 	cleanupMu    sync.Mutex
 	cleanupTimer *time.Timer
 }
@@ -23,17 +30,41 @@ type Session struct {
 type SessionManager struct {
 	mu       sync.RWMutex
 	sessions map[string]*Session
+	budget   *EntityBudget // shared with every game, may be nil
+
+	// rejoinGrace is how long dropped ships wait for their client, and
+	// idleTimeout how long an empty session lingers. Set them before the
+	// manager is shared: disconnect and cleanup paths read them unlocked.
+	rejoinGrace time.Duration
+	idleTimeout time.Duration
 }
 
 // NewSessionManager creates a new SessionManager
 func NewSessionManager() *SessionManager {
 	return &SessionManager{
-		sessions: make(map[string]*Session),
+		sessions:    make(map[string]*Session),
+		rejoinGrace: DefaultRejoinGrace,
+		idleTimeout: DefaultSessionIdleTimeout,
 	}
 }
 
+// SessionOptions are chosen by the host when creating a session
+type SessionOptions struct {
+	CloseWhenEmpty bool
+	Seed           int64      // 0 = random
+	MobWeights     MobWeights // zero value = the preset's mix
+	Preset         string     // match rules preset ("" = classic)
+	Overrides      RuleOverrides
+}
+
 // CreateSession creates a new game session. Returns nil if limit reached.
 func (sm *SessionManager) CreateSession(name string) *Session {
+	return sm.CreateSessionWith(name, SessionOptions{})
+}
+
+// CreateSessionWith creates a new game session with the given options.
+// Returns nil if limit reached.
+func (sm *SessionManager) CreateSessionWith(name string, opts SessionOptions) *Session {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
@@ -41,13 +72,26 @@ func (sm *SessionManager) CreateSession(name string) *Session {
 		return nil
 	}
 
+	cfg, err := ResolvePreset(opts.Preset, opts.Overrides)
+	if err != nil {
+		return nil
+	}
 	id := GenerateUUID()
-	game := NewGame()
+	cfg.Seed = opts.Seed
+	if opts.MobWeights != (MobWeights{}) {
+		cfg.MobWeights = opts.MobWeights
+	}
+	game := NewGameWithConfig(cfg)
+	game.budget = sm.budget
+	game.tag = "session " + id
 	sess := &Session{
-		ID:   id,
-		Name: name,
-		Game: game,
+		ID:             id,
+		Name:           name,
+		Game:           game,
+		CloseWhenEmpty: opts.CloseWhenEmpty,
 	}
+	// Idle kicks happen inside the game loop, under the game lock
+	game.onKick = func(string) { go sm.afterRemove(sess) }
 	sm.sessions[id] = sess
 	go game.Run()
 	return sess
@@ -80,23 +124,49 @@ func (sm *SessionManager) RemovePlayer(sessionID, playerID string) {
 		return
 	}
 	sess.Game.RemovePlayer(playerID)
+	sm.afterRemove(sess)
+}
 
-	// Clean up empty sessions after idle timeout
-	if sess.Game.PlayerCount() == 0 {
-		sess.scheduleCleanup(SessionIdleTimeout, func() {
-			if sess.Game.PlayerCount() != 0 {
-				return
-			}
-			sess.Game.Stop()
-			sm.mu.Lock()
-			if sm.sessions[sessionID] == sess {
-				delete(sm.sessions, sessionID)
-			}
-			sm.mu.Unlock()
+// afterRemove schedules cleanup once a session's last player is gone
+func (sm *SessionManager) afterRemove(sess *Session) {
+	// A private room goes with its host, even with guests or bots left
+	if sess.CloseWhenEmpty && sess.Game.HostLeft() {
+		sess.cancelCleanup()
+		sess.Game.End("host left")
+		sm.remove(sess)
+		return
+	}
+	// Clean up empty sessions after idle timeout (or right away if asked to)
+	if sess.Game.HumanCount() == 0 {
+		if sess.CloseWhenEmpty {
+			sess.cancelCleanup()
+			sm.closeIfEmpty(sess)
+			return
+		}
+		sess.scheduleCleanup(sm.idleTimeout, func() {
+			sm.closeIfEmpty(sess)
 		})
 	}
 }
 
+// closeIfEmpty stops and removes a session that still has no players
+func (sm *SessionManager) closeIfEmpty(sess *Session) {
+	if sess.Game.HumanCount() != 0 {
+		return
+	}
+	sess.Game.Stop()
+	sm.remove(sess)
+}
+
+// remove drops a stopped session from the manager
+func (sm *SessionManager) remove(sess *Session) {
+	sm.mu.Lock()
+	if sm.sessions[sess.ID] == sess {
+		delete(sm.sessions, sess.ID)
+	}
+	sm.mu.Unlock()
+}
+
 // ListSessions returns info about all active sessions
 func (sm *SessionManager) ListSessions() []SessionInfo {
 	sm.mu.RLock()
@@ -105,9 +175,32 @@ func (sm *SessionManager) ListSessions() []SessionInfo {
 	list := make([]SessionInfo, 0, len(sm.sessions))
 	for _, sess := range sm.sessions {
 		list = append(list, SessionInfo{
-			ID:      sess.ID,
-			Name:    sess.Name,
-			Players: sess.Game.PlayerCount(),
+			ID:         sess.ID,
+			Name:       sess.Name,
+			Players:    sess.Game.PlayerCount(),
+			Seed:       sess.Game.Seed(),
+			Spectators: sess.Game.SpectatorCount(),
+		})
+	}
+	return list
+}
+
+// DebugSessions returns per-session tick timings for /api/debug
+func (sm *SessionManager) DebugSessions() []SessionDebug {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	list := make([]SessionDebug, 0, len(sm.sessions))
+	for _, sess := range sm.sessions {
+		list = append(list, SessionDebug{
+			SessionInfo: SessionInfo{
+				ID:         sess.ID,
+				Name:       sess.Name,
+				Players:    sess.Game.PlayerCount(),
+				Seed:       sess.Game.Seed(),
+				Spectators: sess.Game.SpectatorCount(),
+			},
+			Tick: sess.Game.TickStats(),
 		})
 	}
 	return list
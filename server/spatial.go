@@ -1,72 +1,119 @@
 package main
 
+import "math"
+
 const (
-	SpatialCellSize = 80.0 // broad-phase cell size; large entities (SDRadius=100) span multiple cells via InsertCircle
-	SpatialCols     = 51   // ceil(4000/80) + 1
-	SpatialRows     = 51
+	SpatialCellSize    = 80.0 // default broad-phase cell size; large entities (SDRadius=100) span multiple cells via InsertCircle
+	MinSpatialCellSize = 20.0
+	MaxSpatialCellSize = 500.0
 )
 
 // EntityRef identifies an entity in the grid
 type EntityRef struct {
-	Kind byte // 'p'=player, 'r'=projectile, 'm'=mob, 'a'=asteroid, 'k'=pickup
+	Kind byte // 'p'=player, 'r'=projectile, 'm'=mob, 'a'=asteroid, 'k'=pickup, 'o'=static asteroid
 	Idx  int  // index into the corresponding flat list
 }
 
-// SpatialGrid is a fixed-size grid for broad-phase collision queries
+// SpatialGrid is a fixed-size grid for broad-phase collision queries.
+// The zero value uses SpatialCellSize; use NewSpatialGrid to pick another.
 type SpatialGrid struct {
-	cells [SpatialCols * SpatialRows][]EntityRef
+	cellSize float64
+	cols     int
+	rows     int
+	cells    [][]EntityRef
+}
+
+// NewSpatialGrid creates a grid covering the world with the given cell size
+func NewSpatialGrid(cellSize float64) *SpatialGrid {
+	g := &SpatialGrid{}
+	g.init(cellSize)
+	return g
+}
+
+func (g *SpatialGrid) init(cellSize float64) {
+	if cellSize <= 0 {
+		cellSize = SpatialCellSize
+	}
+	cellSize = Clamp(cellSize, MinSpatialCellSize, MaxSpatialCellSize)
+	g.cellSize = cellSize
+	g.cols = int(math.Ceil(WorldWidth/cellSize)) + 1
+	g.rows = int(math.Ceil(WorldHeight/cellSize)) + 1
+	g.cells = make([][]EntityRef, g.cols*g.rows)
+}
+
+// CellSize returns the grid's cell size
+func (g *SpatialGrid) CellSize() float64 {
+	if g.cells == nil {
+		g.init(0)
+	}
+	return g.cellSize
 }
 
 // Clear resets all cells (keeps allocated capacity)
 func (g *SpatialGrid) Clear() {
+	if g.cells == nil {
+		g.init(0)
+	}
 	for i := range g.cells {
 		g.cells[i] = g.cells[i][:0]
 	}
 }
 
-func cellIdx(x, y float64) int {
-	cx := int(x / SpatialCellSize)
-	cy := int(y / SpatialCellSize)
+func (g *SpatialGrid) cellIdx(x, y float64) int {
+	cx := int(x / g.cellSize)
+	cy := int(y / g.cellSize)
 	if cx < 0 {
 		cx = 0
-	} else if cx >= SpatialCols {
-		cx = SpatialCols - 1
+	} else if cx >= g.cols {
+		cx = g.cols - 1
 	}
 	if cy < 0 {
 		cy = 0
-	} else if cy >= SpatialRows {
-		cy = SpatialRows - 1
+	} else if cy >= g.rows {
+		cy = g.rows - 1
 	}
-	return cy*SpatialCols + cx
+	return cy*g.cols + cx
 }
 
-// Insert adds an entity reference at the given position
-func (g *SpatialGrid) Insert(x, y float64, ref EntityRef) {
-	idx := cellIdx(x, y)
-	g.cells[idx] = append(g.cells[idx], ref)
-}
-
-// InsertCircle adds an entity reference to all cells overlapping its bounding box
-func (g *SpatialGrid) InsertCircle(x, y, radius float64, ref EntityRef) {
-	minCX := int((x - radius) / SpatialCellSize)
-	maxCX := int((x + radius) / SpatialCellSize)
-	minCY := int((y - radius) / SpatialCellSize)
-	maxCY := int((y + radius) / SpatialCellSize)
+// cellRange returns the clamped cell bounds overlapping a circle's bounding box
+func (g *SpatialGrid) cellRange(x, y, radius float64) (minCX, maxCX, minCY, maxCY int) {
+	minCX = int((x - radius) / g.cellSize)
+	maxCX = int((x + radius) / g.cellSize)
+	minCY = int((y - radius) / g.cellSize)
+	maxCY = int((y + radius) / g.cellSize)
 	if minCX < 0 {
 		minCX = 0
 	}
-	if maxCX >= SpatialCols {
-		maxCX = SpatialCols - 1
+	if maxCX >= g.cols {
+		maxCX = g.cols - 1
 	}
 	if minCY < 0 {
 		minCY = 0
 	}
-	if maxCY >= SpatialRows {
-		maxCY = SpatialRows - 1
+	if maxCY >= g.rows {
+		maxCY = g.rows - 1
+	}
+	return
+}
+
+// Insert adds an entity reference at the given position
+func (g *SpatialGrid) Insert(x, y float64, ref EntityRef) {
+	if g.cells == nil {
+		g.init(0)
 	}
+	idx := g.cellIdx(x, y)
+	g.cells[idx] = append(g.cells[idx], ref)
+}
+
+// InsertCircle adds an entity reference to all cells overlapping its bounding box
+func (g *SpatialGrid) InsertCircle(x, y, radius float64, ref EntityRef) {
+	if g.cells == nil {
+		g.init(0)
+	}
+	minCX, maxCX, minCY, maxCY := g.cellRange(x, y, radius)
 	for cy := minCY; cy <= maxCY; cy++ {
 		for cx := minCX; cx <= maxCX; cx++ {
-			idx := cy*SpatialCols + cx
+			idx := cy*g.cols + cx
 			g.cells[idx] = append(g.cells[idx], ref)
 		}
 	}
@@ -74,55 +121,69 @@ func (g *SpatialGrid) InsertCircle(x, y, radius float64, ref EntityRef) {
 
 // Query returns all entity refs in cells that overlap the given bounding box
 func (g *SpatialGrid) Query(x, y, radius float64) []EntityRef {
-	minCX := int((x - radius) / SpatialCellSize)
-	maxCX := int((x + radius) / SpatialCellSize)
-	minCY := int((y - radius) / SpatialCellSize)
-	maxCY := int((y + radius) / SpatialCellSize)
-	if minCX < 0 {
-		minCX = 0
-	}
-	if maxCX >= SpatialCols {
-		maxCX = SpatialCols - 1
-	}
-	if minCY < 0 {
-		minCY = 0
-	}
-	if maxCY >= SpatialRows {
-		maxCY = SpatialRows - 1
-	}
-	var result []EntityRef
-	for cy := minCY; cy <= maxCY; cy++ {
-		for cx := minCX; cx <= maxCX; cx++ {
-			idx := cy*SpatialCols + cx
-			result = append(result, g.cells[idx]...)
-		}
-	}
-	return result
+	return g.QueryBuf(x, y, radius, nil)
 }
 
 // QueryBuf appends results to buf and returns the extended slice, avoiding per-call allocation
 func (g *SpatialGrid) QueryBuf(x, y, radius float64, buf []EntityRef) []EntityRef {
-	minCX := int((x - radius) / SpatialCellSize)
-	maxCX := int((x + radius) / SpatialCellSize)
-	minCY := int((y - radius) / SpatialCellSize)
-	maxCY := int((y + radius) / SpatialCellSize)
-	if minCX < 0 {
-		minCX = 0
-	}
-	if maxCX >= SpatialCols {
-		maxCX = SpatialCols - 1
-	}
-	if minCY < 0 {
-		minCY = 0
-	}
-	if maxCY >= SpatialRows {
-		maxCY = SpatialRows - 1
+	if g.cells == nil {
+		return buf
 	}
+	minCX, maxCX, minCY, maxCY := g.cellRange(x, y, radius)
 	for cy := minCY; cy <= maxCY; cy++ {
 		for cx := minCX; cx <= maxCX; cx++ {
-			idx := cy*SpatialCols + cx
+			idx := cy*g.cols + cx
 			buf = append(buf, g.cells[idx]...)
 		}
 	}
 	return buf
 }
+
+// Occupancy appends every non-empty cell and its ref count to buf
+func (g *SpatialGrid) Occupancy(buf []DebugCell) []DebugCell {
+	for i, cell := range g.cells {
+		if len(cell) > 0 {
+			buf = append(buf, DebugCell{Cell: i, N: len(cell)})
+		}
+	}
+	return buf
+}
+
+// Cols returns the number of grid columns
+func (g *SpatialGrid) Cols() int {
+	if g.cells == nil {
+		g.init(0)
+	}
+	return g.cols
+}
+
+// QueryBufUnique is QueryBuf with duplicates removed. Entities inserted with
+// InsertCircle appear in every cell they overlap, so a multi-cell query can
+// return them more than once. Single-cell queries skip the dedup pass.
+func (g *SpatialGrid) QueryBufUnique(x, y, radius float64, buf []EntityRef) []EntityRef {
+	if g.cells == nil {
+		return buf
+	}
+	start := len(buf)
+	minCX, maxCX, minCY, maxCY := g.cellRange(x, y, radius)
+	buf = g.QueryBuf(x, y, radius, buf)
+	if minCX == maxCX && minCY == maxCY {
+		return buf
+	}
+	// Results are small (tens of refs), so an in-place linear scan beats a map
+	n := start
+	for i := start; i < len(buf); i++ {
+		dup := false
+		for j := start; j < n; j++ {
+			if buf[j] == buf[i] {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			buf[n] = buf[i]
+			n++
+		}
+	}
+	return buf[:n]
+}
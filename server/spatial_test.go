@@ -95,3 +95,126 @@ func TestSpatialGridBoundaryClamp(t *testing.T) {
 		t.Error("expected to find entity inserted beyond world edge")
 	}
 }
+
+func TestSpatialGridQueriesAcrossCellSizes(t *testing.T) {
+	type ent struct{ x, y, r float64 }
+	ents := []ent{
+		{100, 100, 4}, {1234, 987, 25}, {2000, 2000, SDRadius}, {3990, 10, 50},
+		{5, 3995, 15}, {1999, 2101, 4}, {2600, 1400, 25},
+	}
+	for _, size := range []float64{MinSpatialCellSize, 40, SpatialCellSize, 150, 250, MaxSpatialCellSize} {
+		grid := NewSpatialGrid(size)
+		for i, e := range ents {
+			grid.InsertCircle(e.x, e.y, e.r, EntityRef{Kind: 'm', Idx: i})
+		}
+		// Every pair that actually overlaps must be found by the broad phase
+		for i, a := range ents {
+			queryR := a.r + SDRadius
+			seen := map[int]bool{}
+			for _, ref := range grid.Query(a.x, a.y, queryR) {
+				seen[ref.Idx] = true
+			}
+			for j, b := range ents {
+				if CheckCollision(a.x, a.y, queryR, b.x, b.y, b.r) && !seen[j] {
+					t.Errorf("cell size %.0f: query around %d missed overlapping entity %d", size, i, j)
+				}
+			}
+		}
+	}
+}
+
+func TestSpatialGridDefaultCellSize(t *testing.T) {
+	if got := NewSpatialGrid(0).CellSize(); got != SpatialCellSize {
+		t.Errorf("zero cell size should default to %f, got %f", SpatialCellSize, got)
+	}
+}
+
+func benchmarkSpatialQuery(b *testing.B, cellSize float64) {
+	grid := NewSpatialGrid(cellSize)
+	// Roughly a busy session: 20 players, 8 mobs, 300 projectiles
+	for i := 0; i < 20; i++ {
+		grid.InsertCircle(float64(i*197%4000), float64(i*331%4000), PlayerRadius, EntityRef{Kind: 'p', Idx: i})
+	}
+	for i := 0; i < 8; i++ {
+		grid.InsertCircle(float64(i*509%4000), float64(i*277%4000), TieRadius, EntityRef{Kind: 'm', Idx: i})
+	}
+	for i := 0; i < 300; i++ {
+		grid.Insert(float64(i*37%4000), float64(i*53%4000), EntityRef{Kind: 'r', Idx: i})
+	}
+	var buf []EntityRef
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x, y := float64(i*37%4000), float64(i*53%4000)
+		buf = grid.QueryBuf(x, y, ProjectileRadius+SDRadius, buf[:0])
+	}
+}
+
+func BenchmarkSpatialQueryCell40(b *testing.B)  { benchmarkSpatialQuery(b, 40) }
+func BenchmarkSpatialQueryCell80(b *testing.B)  { benchmarkSpatialQuery(b, 80) }
+func BenchmarkSpatialQueryCell160(b *testing.B) { benchmarkSpatialQuery(b, 160) }
+func BenchmarkSpatialQueryCell320(b *testing.B) { benchmarkSpatialQuery(b, 320) }
+
+func TestSpatialGridQueryUniqueMultiCellEntity(t *testing.T) {
+	grid := NewSpatialGrid(SpatialCellSize)
+	// A Star Destroyer spans several cells
+	grid.InsertCircle(400, 400, SDRadius, EntityRef{Kind: 'm', Idx: 7})
+	grid.Insert(410, 410, EntityRef{Kind: 'r', Idx: 1})
+
+	raw := grid.QueryBuf(400, 400, SDRadius, nil)
+	mobHits := 0
+	for _, r := range raw {
+		if r.Kind == 'm' {
+			mobHits++
+		}
+	}
+	if mobHits < 2 {
+		t.Fatalf("expected duplicate refs without dedup, got %d", mobHits)
+	}
+
+	unique := grid.QueryBufUnique(400, 400, SDRadius, nil)
+	mobHits, projHits := 0, 0
+	for _, r := range unique {
+		switch r.Kind {
+		case 'm':
+			mobHits++
+		case 'r':
+			projHits++
+		}
+	}
+	if mobHits != 1 || projHits != 1 {
+		t.Errorf("expected each entity once with dedup, got mob=%d proj=%d", mobHits, projHits)
+	}
+}
+
+func TestSpatialGridQueryUniqueKeepsExistingBuf(t *testing.T) {
+	grid := NewSpatialGrid(SpatialCellSize)
+	grid.InsertCircle(400, 400, SDRadius, EntityRef{Kind: 'm', Idx: 0})
+	buf := []EntityRef{{Kind: 'm', Idx: 0}}
+	buf = grid.QueryBufUnique(400, 400, SDRadius, buf)
+	if len(buf) != 2 {
+		t.Errorf("dedup should only apply to newly appended refs, got %d", len(buf))
+	}
+}
+
+func benchmarkQueryLargeEntity(b *testing.B, unique bool) {
+	grid := NewSpatialGrid(SpatialCellSize)
+	for i := 0; i < 8; i++ {
+		grid.InsertCircle(float64(300+i*400), 2000, SDRadius, EntityRef{Kind: 'm', Idx: i})
+	}
+	for i := 0; i < 20; i++ {
+		grid.InsertCircle(float64(250+i*180), 2050, PlayerRadius, EntityRef{Kind: 'p', Idx: i})
+	}
+	var buf []EntityRef
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x := float64(300 + (i%8)*400)
+		if unique {
+			buf = grid.QueryBufUnique(x, 2000, SDRadius+PlayerRadius, buf[:0])
+		} else {
+			buf = grid.QueryBuf(x, 2000, SDRadius+PlayerRadius, buf[:0])
+		}
+	}
+}
+
+func BenchmarkSpatialQueryLargeEntity(b *testing.B)       { benchmarkQueryLargeEntity(b, false) }
+func BenchmarkSpatialQueryLargeEntityUnique(b *testing.B) { benchmarkQueryLargeEntity(b, true) }
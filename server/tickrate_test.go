@@ -0,0 +1,116 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// stepsFor returns the step count and dt that cover secs at the given rate
+func stepsFor(secs float64, hz int) (int, float64) {
+	return int(secs * float64(hz)), 1.0 / float64(hz)
+}
+
+func TestPlayerMotionTickRateIndependent(t *testing.T) {
+	run := func(hz int) *Player {
+		p := NewPlayer("p1", "Rate", 0)
+		p.X, p.Y = 1000, 2000
+		p.VX, p.VY = 0, 0
+		p.Rotation, p.TargetR = 0, 0
+		p.TargetX, p.TargetY = 1e6, 2000
+		n, dt := stepsFor(1.5, hz)
+		for i := 0; i < n; i++ {
+			p.Update(dt)
+		}
+		return p
+	}
+	a, b := run(30), run(60)
+	if d := Distance(a.X, a.Y, b.X, b.Y); d > 10 {
+		t.Errorf("30Hz and 60Hz positions differ by %.1fpx: (%.1f,%.1f) vs (%.1f,%.1f)", d, a.X, a.Y, b.X, b.Y)
+	}
+	if d := math.Abs(a.VX - b.VX); d > 0.02*b.VX {
+		t.Errorf("30Hz and 60Hz speeds differ: %.1f vs %.1f", a.VX, b.VX)
+	}
+}
+
+func TestPlayerCoastTickRateIndependent(t *testing.T) {
+	run := func(hz int) *Player {
+		p := NewPlayer("p1", "Coast", 0)
+		p.X, p.Y = 1000, 2000
+		p.VX, p.VY = 300, 0
+		// Pointer on the ship: no thrust, brake friction only
+		p.TargetX, p.TargetY = p.X, p.Y
+		p.Rotation, p.TargetR = 0, 0
+		n, dt := stepsFor(1, hz)
+		for i := 0; i < n; i++ {
+			p.TargetX = p.X
+			p.Update(dt)
+		}
+		return p
+	}
+	a, b := run(30), run(60)
+	if d := math.Abs(a.VX - b.VX); d > 1 {
+		t.Errorf("friction should not depend on tick rate: %.2f vs %.2f", a.VX, b.VX)
+	}
+	if d := math.Abs(a.X - b.X); d > 10 {
+		t.Errorf("30Hz and 60Hz coast distances differ by %.1fpx", d)
+	}
+}
+
+func TestProjectileTravelTickRateIndependent(t *testing.T) {
+	run := func(hz int) *Projectile {
+		owner := NewPlayer("p1", "Gun", 0)
+		owner.X, owner.Y, owner.Rotation = 1000, 1000, 0.3
+		owner.VX, owner.VY = 100, -50
+		proj := NewProjectile(owner)
+		n, dt := stepsFor(0.5, hz)
+		for i := 0; i < n; i++ {
+			proj.Update(dt)
+		}
+		return proj
+	}
+	a, b := run(30), run(60)
+	if d := Distance(a.X, a.Y, b.X, b.Y); d > 0.01 {
+		t.Errorf("projectile positions differ by %.3fpx across tick rates", d)
+	}
+	if math.Abs(a.Life-b.Life) > 1e-9 {
+		t.Errorf("projectile lifetimes differ: %.4f vs %.4f", a.Life, b.Life)
+	}
+}
+
+func TestDampingMatchesPerTickAtTickRate(t *testing.T) {
+	if got := Damping(PlayerFriction, 1.0/TickRate); math.Abs(got-PlayerFriction) > 1e-12 {
+		t.Errorf("at the base tick rate damping should equal the per-tick multiplier, got %v", got)
+	}
+	half := Damping(MobFriction, 2.0/TickRate)
+	if math.Abs(half-MobFriction*MobFriction) > 1e-12 {
+		t.Errorf("a double-length step should apply the multiplier twice, got %v", half)
+	}
+}
+
+func TestMobRepulsionTickRateIndependent(t *testing.T) {
+	run := func(hz int) (*Mob, *Mob) {
+		g := NewGame()
+		a, b := NewTieMob(), NewTieMob()
+		a.X, a.Y, a.VX, a.VY = 2000, 2000, 0, 0
+		b.X, b.Y, b.VX, b.VY = 2000+a.Radius, 2000, 0, 0
+		g.mobs[a.ID] = a
+		g.mobs[b.ID] = b
+		// Positions are held so both rates integrate the same force
+		n, dt := stepsFor(0.5, hz)
+		for i := 0; i < n; i++ {
+			g.checkMobMobCollisions(dt)
+		}
+		return a, b
+	}
+	a30, b30 := run(30)
+	a60, b60 := run(60)
+	if !a30.Alive || !b30.Alive {
+		t.Fatal("a gentle overlap should repel, not explode")
+	}
+	if a60.VX >= 0 || b60.VX <= 0 {
+		t.Fatalf("mobs should be pushed apart, got vx %.2f / %.2f", a60.VX, b60.VX)
+	}
+	if math.Abs(a30.VX-a60.VX) > 1e-9 || math.Abs(b30.VX-b60.VX) > 1e-9 {
+		t.Errorf("repulsion differs across tick rates: %.3f/%.3f vs %.3f/%.3f", a30.VX, b30.VX, a60.VX, b60.VX)
+	}
+}
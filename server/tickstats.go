@@ -0,0 +1,71 @@
+package main
+
+import (
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// SlowTickThreshold is the update duration that counts as a slow tick
+	SlowTickThreshold = TickDuration * 8 / 10
+	// slowTickLogEvery rate-limits slow-tick log lines per session
+	slowTickLogEvery = time.Second
+)
+
+// TickStats is a snapshot of a session's update() timings
+type TickStats struct {
+	Ticks     uint64  `json:"ticks"`
+	SlowTicks uint64  `json:"slow_ticks"`
+	AvgMs     float64 `json:"avg_ms"`
+	MaxMs     float64 `json:"max_ms"`
+}
+
+// tickTimer accumulates tick durations; written by the game loop, read by /api/debug
+type tickTimer struct {
+	count   atomic.Uint64
+	slow    atomic.Uint64
+	totalNs atomic.Int64
+	maxNs   atomic.Int64
+
+	lastLog    time.Time // game loop only
+	suppressed int       // slow ticks not logged since lastLog
+}
+
+// record adds one tick duration, logging if it was slow
+func (t *tickTimer) record(sessionTag string, d time.Duration) {
+	t.count.Add(1)
+	t.totalNs.Add(int64(d))
+	if int64(d) > t.maxNs.Load() {
+		t.maxNs.Store(int64(d))
+	}
+	if d < SlowTickThreshold {
+		return
+	}
+	t.slow.Add(1)
+	now := time.Now()
+	if now.Sub(t.lastLog) < slowTickLogEvery {
+		t.suppressed++
+		return
+	}
+	if t.suppressed > 0 {
+		log.Printf("slow tick in %s: %v (budget %v, %d more since last report)", sessionTag, d, TickDuration, t.suppressed)
+	} else {
+		log.Printf("slow tick in %s: %v (budget %v)", sessionTag, d, TickDuration)
+	}
+	t.lastLog = now
+	t.suppressed = 0
+}
+
+// snapshot returns the current stats
+func (t *tickTimer) snapshot() TickStats {
+	s := TickStats{
+		Ticks:     t.count.Load(),
+		SlowTicks: t.slow.Load(),
+		MaxMs:     float64(t.maxNs.Load()) / float64(time.Millisecond),
+	}
+	if s.Ticks > 0 {
+		s.AvgMs = float64(t.totalNs.Load()) / float64(s.Ticks) / float64(time.Millisecond)
+	}
+	return s
+}
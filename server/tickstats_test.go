@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(prev) })
+	return &buf
+}
+
+func TestSlowTickLogged(t *testing.T) {
+	buf := captureLog(t)
+	g := NewGame()
+	g.tag = "session test"
+	g.timeTick(func() {
+		g.update()
+		time.Sleep(TickDuration)
+	})
+
+	if !strings.Contains(buf.String(), "slow tick in session test") {
+		t.Fatalf("expected slow-tick log, got %q", buf.String())
+	}
+	st := g.TickStats()
+	if st.Ticks != 1 || st.SlowTicks != 1 {
+		t.Errorf("expected 1 tick / 1 slow tick, got %+v", st)
+	}
+	if st.MaxMs < float64(TickDuration)/float64(time.Millisecond) {
+		t.Errorf("max tick time %.2fms should cover the injected sleep", st.MaxMs)
+	}
+}
+
+func TestFastTickNotLogged(t *testing.T) {
+	buf := captureLog(t)
+	g := NewGame()
+	for i := 0; i < 10; i++ {
+		g.timedUpdate()
+	}
+	if strings.Contains(buf.String(), "slow tick") {
+		t.Errorf("empty game ticks should not be logged as slow: %q", buf.String())
+	}
+	st := g.TickStats()
+	if st.Ticks != 10 || st.SlowTicks != 0 {
+		t.Errorf("expected 10 ticks / 0 slow, got %+v", st)
+	}
+	if st.AvgMs <= 0 || st.AvgMs > st.MaxMs {
+		t.Errorf("avg %.4fms should be positive and <= max %.4fms", st.AvgMs, st.MaxMs)
+	}
+}
+
+func TestSlowTickLogRateLimited(t *testing.T) {
+	buf := captureLog(t)
+	var tt tickTimer
+	for i := 0; i < 5; i++ {
+		tt.record("session x", SlowTickThreshold)
+	}
+	if n := strings.Count(buf.String(), "slow tick"); n != 1 {
+		t.Errorf("expected 1 log line within the rate limit window, got %d", n)
+	}
+	if st := tt.snapshot(); st.SlowTicks != 5 {
+		t.Errorf("all slow ticks should still be counted, got %d", st.SlowTicks)
+	}
+}
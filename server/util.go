@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math"
+	mrand "math/rand"
 )
 
 // GenerateID returns a random hex string of the given byte length
@@ -14,6 +15,19 @@ func GenerateID(byteLen int) string {
 	return hex.EncodeToString(b)
 }
 
+// randSource is the randomness entity constructors draw from: the shared
+// process source by default, or a session's seeded *rand.Rand
+type randSource interface {
+	Float64() float64
+	Intn(n int) int
+}
+
+// globalRand is the process-wide randSource
+type globalRand struct{}
+
+func (globalRand) Float64() float64 { return randFloat() }
+func (globalRand) Intn(n int) int   { return mrand.Intn(n) }
+
 // GenerateUUID returns a random UUID v4 string
 func GenerateUUID() string {
 	b := make([]byte, 16)
@@ -59,6 +73,12 @@ func NormalizeAngle(a float64) float64 {
 	return a
 }
 
+// Damping converts a per-tick velocity multiplier (tuned at TickRate) into
+// the multiplier for a step of dt seconds, so friction is tick-rate independent
+func Damping(perTick, dt float64) float64 {
+	return math.Pow(perTick, dt*TickRate)
+}
+
 // round1 rounds a float64 to 1 decimal place to reduce JSON payload size
 func round1(x float64) float64 {
 	return math.Round(x*10) / 10